@@ -0,0 +1,88 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/timtatt/fivecrowns/registry"
+)
+
+// serves the admin api for registering and deregistering remote bots
+// changes must send the token as a bearer token. without a token the registry can only be listed,
+// as registering a bot makes the server send requests to any url
+func configureRegistry(mux *http.ServeMux, reg *registry.Registry, token string) {
+
+	mux.HandleFunc("GET /api/bots", func(res http.ResponseWriter, req *http.Request) {
+		writeJSON(res, reg.List())
+	})
+
+	if token == "" {
+		slog.Warn("no admin token given, bots cannot be registered or deregistered")
+		return
+	}
+
+	mux.HandleFunc("POST /api/bots", authorised(token, func(res http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+
+		var registration registry.Registration
+		if err := json.NewDecoder(req.Body).Decode(&registration); err != nil {
+			slog.Error("unable to unmarshal request", "err", err)
+			res.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		status, err := reg.Register(registration)
+
+		if err != nil {
+			writeRegistryError(res, err)
+			return
+		}
+
+		res.Header().Set("Content-Type", "application/json")
+		res.WriteHeader(http.StatusCreated)
+		writeJSON(res, status)
+	}))
+
+	mux.HandleFunc("DELETE /api/bots/{name}", authorised(token, func(res http.ResponseWriter, req *http.Request) {
+		if err := reg.Deregister(req.PathValue("name")); err != nil {
+			writeRegistryError(res, err)
+			return
+		}
+
+		res.WriteHeader(http.StatusNoContent)
+	}))
+
+}
+
+func authorised(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		if !hasToken(token, req) {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		next(res, req)
+	}
+}
+
+// checks the request sends the admin token as a bearer token. nobody has it when there is no token
+func hasToken(token string, req *http.Request) bool {
+	return token != "" && subtle.ConstantTimeCompare([]byte(req.Header.Get("Authorization")), []byte("Bearer "+token)) == 1
+}
+
+func writeRegistryError(res http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, registry.ErrInvalid):
+		http.Error(res, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, registry.ErrNotFound):
+		http.Error(res, err.Error(), http.StatusNotFound)
+	case errors.Is(err, registry.ErrExists), errors.Is(err, registry.ErrBuiltIn):
+		http.Error(res, err.Error(), http.StatusConflict)
+	default:
+		slog.Error("unable to update registry", "err", err)
+		res.WriteHeader(http.StatusInternalServerError)
+	}
+}
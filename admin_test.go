@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots/wsbot"
+	"github.com/timtatt/fivecrowns/registry"
+)
+
+// serves the admin api with a registry which already has a remote bot called mybot
+func newAdminServer(t *testing.T, token string) (*httptest.Server, *registry.Registry) {
+	config := registry.DefaultConfig
+	config.Path = ""
+	config.Hub = wsbot.NewHub(time.Second)
+
+	reg, err := registry.New(registeredBots(), config)
+	require.NoError(t, err)
+	t.Cleanup(reg.Close)
+
+	_, err = reg.Register(registry.Registration{Name: "mybot", Transport: registry.TransportWS, Owner: "tim"})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	configureRegistry(mux, reg, token)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, reg
+}
+
+func adminRequest(t *testing.T, method string, url string, body string, token string) int {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+
+	return res.StatusCode
+}
+
+const newBot = `{"name":"otherbot","transport":"http","url":"http://localhost:8080/bots/otherbot","owner":"tim"}`
+
+func TestAdmin(t *testing.T) {
+
+	server, reg := newAdminServer(t, "secret")
+
+	assert.Equal(t, http.StatusOK, adminRequest(t, http.MethodGet, server.URL+"/api/bots", "", ""))
+
+	assert.Equal(t, http.StatusUnauthorized, adminRequest(t, http.MethodPost, server.URL+"/api/bots", newBot, ""))
+	assert.Equal(t, http.StatusUnauthorized, adminRequest(t, http.MethodPost, server.URL+"/api/bots", newBot, "wrong"))
+	assert.Equal(t, http.StatusUnauthorized, adminRequest(t, http.MethodDelete, server.URL+"/api/bots/mybot", "", ""))
+
+	assert.Equal(t, http.StatusCreated, adminRequest(t, http.MethodPost, server.URL+"/api/bots", newBot, "secret"))
+	assert.Equal(t, http.StatusNoContent, adminRequest(t, http.MethodDelete, server.URL+"/api/bots/mybot", "", "secret"))
+
+	_, ok := reg.Bot("mybot")
+	assert.False(t, ok)
+	_, ok = reg.Bot("otherbot")
+	assert.True(t, ok)
+}
+
+func TestAdminWithoutToken(t *testing.T) {
+
+	server, reg := newAdminServer(t, "")
+
+	assert.Equal(t, http.StatusOK, adminRequest(t, http.MethodGet, server.URL+"/api/bots", "", ""))
+
+	// without a token nobody can change the registry, even without sending a token themselves
+	assert.Equal(t, http.StatusMethodNotAllowed, adminRequest(t, http.MethodPost, server.URL+"/api/bots", newBot, ""))
+	assert.Equal(t, http.StatusNotFound, adminRequest(t, http.MethodDelete, server.URL+"/api/bots/mybot", "", ""))
+
+	_, ok := reg.Bot("mybot")
+	assert.True(t, ok)
+	_, ok = reg.Bot("otherbot")
+	assert.False(t, ok)
+}
@@ -57,9 +57,11 @@ function shuffleDeck(cards, seed) {
 
   const deck = [...cards];
 
-  deck.sort(function () {
-    return random() > 0.5 ? 1 : -1;
-  });
+  // fisher-yates shuffle, sorting with a random comparator is biased
+  for (let i = deck.length - 1; i > 0; i--) {
+    const j = Math.floor(random() * (i + 1));
+    [deck[i], deck[j]] = [deck[j], deck[i]];
+  }
 
   return deck;
 }
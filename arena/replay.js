@@ -0,0 +1,115 @@
+$(function () {
+  let frames = [];
+
+  loadMatches();
+
+  async function loadMatches() {
+    const response = await fetch("/api/matches");
+    const matches = await response.json();
+
+    let options = "";
+    for (const match of matches) {
+      const totals = match.totals ? ` (${match.totals.join(", ")})` : "";
+      options += `<option value="${match.id}">${match.id}: ${match.players.join(" vs ")}${totals}</option>`;
+    }
+
+    $("#matches").html(options);
+
+    if (matches.length > 0) {
+      await loadMatch(matches[0].id);
+    }
+  }
+
+  async function loadMatch(id) {
+    const response = await fetch(`/api/matches/${id}/frames`);
+    frames = await response.json();
+
+    $("#frame").attr("max", Math.max(frames.length - 1, 0)).val(0);
+
+    render();
+  }
+
+  $("#matches").on("change", async function (e) {
+    await loadMatch($(e.currentTarget).val());
+  });
+
+  $("#newMatch").on("click", async function () {
+    const players = $("#newMatchPlayers")
+      .val()
+      .split(":")
+      .filter((p) => p !== "");
+
+    const response = await fetch("/api/matches", {
+      method: "POST",
+      body: JSON.stringify({
+        players,
+        seed: parseInt($("#newMatchSeed").val() || "0"),
+      }),
+    });
+
+    const match = await response.json();
+
+    await loadMatches();
+    $("#matches").val(match.id);
+    await loadMatch(match.id);
+  });
+
+  $("#frame").on("input", render);
+
+  $("#prev").on("click", function () {
+    $("#frame").val(parseInt($("#frame").val()) - 1);
+    render();
+  });
+
+  $("#next").on("click", function () {
+    $("#frame").val(parseInt($("#frame").val()) + 1);
+    render();
+  });
+
+  function render() {
+    const frame = frames[parseInt($("#frame").val())];
+
+    if (!frame) {
+      $("#frameSummary").html("No turns recorded");
+      $("#discardPile").html("");
+      $("#players").html("");
+      return;
+    }
+
+    const player = frame.players[frame.seat];
+    $("#frameSummary").html(
+      `Round ${frame.round}, Turn ${frame.turn}: ${player.name} drew ${frame.newestCard} from the ${frame.stack} and discarded ${frame.discarded}`,
+    );
+
+    $("#discardPile").html(renderCards(frame.discard, { size: "xs" }));
+
+    let players = "";
+    for (const [seat, p] of frame.players.entries()) {
+      let sequences = "";
+      let score = 0;
+
+      for (const seq of p.sequences || []) {
+        if (seq.length < 3) {
+          score += scoreSequence(seq);
+        }
+
+        sequences += `<li class="list-group-item"><div class="hand hand-sm">${renderCards(seq, { size: "xs" })}</div></li>`;
+      }
+
+      const active = seat === frame.seat ? "border-primary" : "";
+
+      players += `<div class="card mb-3 ${active}">
+        <div class="card-header">
+          <h6 class="card-title mb-0">${p.name}</h6>
+          <small class="text-body-secondary">Score: ${score}, Flop: ${p.flop}</small>
+        </div>
+        <div class="card-body">
+          <div class="hand hand-sm mb-2">${renderCards(p.hand || [], { size: "sm" })}</div>
+          <ul class="list-group list-group-flush">${sequences}</ul>
+        </div>
+      </div>`;
+    }
+
+    $("#players").html(players);
+  }
+});
@@ -82,7 +82,7 @@ $(function () {
       method: "POST",
       body: JSON.stringify({
         action,
-        discard: discard.split(":"),
+        discard: discard ? discard.split(":") : [],
         playerCount: 1,
         lastTurn: false,
         newestCard: "",
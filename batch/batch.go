@@ -0,0 +1,271 @@
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/engine"
+	"github.com/timtatt/fivecrowns/tournament"
+)
+
+// Batch plays the same table of bots over a range of seeds and summarises how each bot scored
+// game i is seeded with Seed+i, and the seats rotate every game so no bot always plays first
+
+type Config struct {
+	// the bots at the table, each one gets a fresh bot every game
+	Bots  []tournament.Entrant
+	Games int
+	Seed  uint64
+	// number of games to play at the same time. defaults to the number of CPUs
+	Concurrency int
+	// the rules every game is played with, nil for the house rules
+	Rules *game.RuleSet
+}
+
+type Report struct {
+	Games int `json:"games"`
+	// games which ended early are left out of the stats
+	Failed    int        `json:"failed"`
+	FirstSeed uint64     `json:"firstSeed"`
+	LastSeed  uint64     `json:"lastSeed"`
+	Bots      []BotStats `json:"bots"`
+}
+
+type BotStats struct {
+	Name  string `json:"name"`
+	Games int    `json:"games"`
+	// games the bot finished with the lowest total, including ties
+	Wins int `json:"wins"`
+	// penalty of every round the bot played
+	Penalty Summary `json:"penalty"`
+	// fraction of rounds the bot went out first
+	GoOutRate float64 `json:"goOutRate"`
+	// turns taken by the whole table in each round
+	Turns  Summary      `json:"turns"`
+	Rounds []RoundStats `json:"rounds"`
+}
+
+type RoundStats struct {
+	Round     int     `json:"round"`
+	Penalty   Summary `json:"penalty"`
+	GoOutRate float64 `json:"goOutRate"`
+	Turns     Summary `json:"turns"`
+}
+
+// Game is a single game of the batch
+type Game struct {
+	Seed uint64
+	// index of the bot in each seat
+	Seats  []int
+	Result engine.MatchResult
+	Err    error
+}
+
+// plays every game and summarises the results
+// games which fail are counted in the report and their errors returned
+func Run(config Config) (Report, error) {
+	games, err := Play(config)
+
+	if games == nil {
+		return Report{}, err
+	}
+
+	return Summarise(config, games), err
+}
+
+// plays every game concurrently, returning them in seed order
+func Play(config Config) ([]Game, error) {
+
+	if len(config.Bots) < engine.MinPlayers || len(config.Bots) > engine.MaxPlayers {
+		return nil, fmt.Errorf("invalid number of bots: %d", len(config.Bots))
+	} else if config.Games < 1 {
+		return nil, fmt.Errorf("invalid number of games: %d", config.Games)
+	}
+
+	if config.Concurrency < 1 {
+		config.Concurrency = runtime.NumCPU()
+	}
+
+	games := make([]Game, config.Games)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, config.Concurrency)
+
+	for i := range games {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(game *Game) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			game.Seed = config.Seed + uint64(i)
+			game.Seats = seats(len(config.Bots), i)
+
+			table := make([]tournament.Entrant, len(game.Seats))
+			for seat, bot := range game.Seats {
+				table[seat] = config.Bots[bot]
+			}
+
+			game.Result, game.Err = play(table, config.Rules, game.Seed)
+		}(&games[i])
+	}
+
+	wg.Wait()
+
+	var errs error
+	for _, game := range games {
+		errs = errors.Join(errs, game.Err)
+	}
+
+	return games, errs
+}
+
+// rotates the bots around the table by one seat each game
+func seats(bots int, game int) []int {
+	seats := make([]int, bots)
+
+	for seat := range seats {
+		seats[seat] = (seat + game) % bots
+	}
+
+	return seats
+}
+
+// plays a single game with the bots in seat order
+func play(table []tournament.Entrant, rules *game.RuleSet, seed uint64) (engine.MatchResult, error) {
+	players := make([]engine.Player, len(table))
+
+	for i, bot := range table {
+		players[i] = engine.Player{
+			Name: bot.Name,
+			Bot:  bot.NewBot(),
+		}
+	}
+
+	e := engine.NewEngine(players, seed)
+	if rules != nil {
+		e.Rules = *rules
+	}
+
+	res, err := e.Play()
+
+	if err != nil {
+		return res, fmt.Errorf("game with seed %d failed: %w", seed, err)
+	}
+
+	return res, nil
+}
+
+// summarises the games in seed order, so the same games always give the same report
+func Summarise(config Config, games []Game) Report {
+	rules := game.HouseRules
+	if config.Rules != nil {
+		rules = *config.Rules
+	}
+
+	report := Report{
+		Games:     len(games),
+		FirstSeed: config.Seed,
+		LastSeed:  config.Seed + uint64(max(len(games)-1, 0)),
+		Bots:      make([]BotStats, len(config.Bots)),
+	}
+
+	wentOut := make([][]int, len(config.Bots))
+
+	for i, bot := range config.Bots {
+		report.Bots[i] = BotStats{
+			Name:   bot.Name,
+			Rounds: make([]RoundStats, rules.Rounds()),
+		}
+
+		for r := range report.Bots[i].Rounds {
+			report.Bots[i].Rounds[r].Round = rules.FirstRound + r
+		}
+
+		wentOut[i] = make([]int, rules.Rounds())
+	}
+
+	for _, g := range games {
+		if g.Err != nil {
+			report.Failed += 1
+			continue
+		}
+
+		winners := g.Result.Winners()
+
+		for seat, bot := range g.Seats {
+			stats := &report.Bots[bot]
+			stats.Games += 1
+
+			for _, winner := range winners {
+				if winner == seat {
+					stats.Wins += 1
+				}
+			}
+
+			for _, round := range g.Result.Rounds {
+				r := round.Round - rules.FirstRound
+
+				stats.Penalty.Add(float64(round.Scores[seat]))
+				stats.Turns.Add(float64(round.Turns))
+				stats.Rounds[r].Penalty.Add(float64(round.Scores[seat]))
+				stats.Rounds[r].Turns.Add(float64(round.Turns))
+
+				if round.WentOut == seat {
+					wentOut[bot][r] += 1
+				}
+			}
+		}
+	}
+
+	for i := range report.Bots {
+		stats := &report.Bots[i]
+		total := 0
+
+		for r := range stats.Rounds {
+			total += wentOut[i][r]
+			stats.Rounds[r].GoOutRate = rate(wentOut[i][r], stats.Rounds[r].Penalty.Count)
+		}
+
+		stats.GoOutRate = rate(total, stats.Penalty.Count)
+	}
+
+	return report
+}
+
+func rate(n int, of int) float64 {
+	if of == 0 {
+		return 0
+	}
+
+	return float64(n) / float64(of)
+}
+
+// writes the summary of every bot, followed by its round by round breakdown
+func WriteReport(w io.Writer, report Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "played %d games with seeds %d to %d, %d failed\n\n", report.Games, report.FirstSeed, report.LastSeed, report.Failed)
+	fmt.Fprintln(tw, "bot\tgames\twins\tmean penalty/round\tvariance\tgo out rate\tturns/round")
+
+	for _, bot := range report.Bots {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%.2f\t%.1f%%\t%.1f\n", bot.Name, bot.Games, bot.Wins, bot.Penalty.Mean, bot.Penalty.Variance, bot.GoOutRate*100, bot.Turns.Mean)
+	}
+
+	for _, bot := range report.Bots {
+		fmt.Fprintf(tw, "\n%s\n", bot.Name)
+		fmt.Fprintln(tw, "round\tmean penalty\tvariance\tgo out rate\tturns")
+
+		for _, round := range bot.Rounds {
+			fmt.Fprintf(tw, "%d\t%.2f\t%.2f\t%.1f%%\t%.1f\n", round.Round, round.Penalty.Mean, round.Penalty.Variance, round.GoOutRate*100, round.Turns.Mean)
+		}
+	}
+
+	return tw.Flush()
+}
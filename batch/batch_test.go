@@ -0,0 +1,129 @@
+package batch
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/bigbrainbot"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/tournament"
+)
+
+var table = []tournament.Entrant{
+	{Name: "grugbot", NewBot: grugbot.NewGrugBot},
+	{Name: "bigbrainbot", NewBot: bigbrainbot.NewBigBrainBot},
+}
+
+func TestSummary(t *testing.T) {
+
+	var s Summary
+
+	for _, value := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		s.Add(value)
+	}
+
+	assert.Equal(t, 8, s.Count)
+	assert.InDelta(t, 5, s.Mean, 1e-9)
+	assert.InDelta(t, 32.0/7, s.Variance, 1e-9)
+}
+
+func TestRun(t *testing.T) {
+
+	config := Config{
+		Bots:  table,
+		Games: 4,
+		Seed:  10,
+	}
+
+	report, err := Run(config)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Games)
+	assert.Equal(t, uint64(13), report.LastSeed)
+	require.Len(t, report.Bots, 2)
+
+	for _, bot := range report.Bots {
+		assert.Equal(t, 4, bot.Games)
+		assert.Equal(t, 44, bot.Penalty.Count)
+		require.Len(t, bot.Rounds, 11)
+		assert.Equal(t, 3, bot.Rounds[0].Round)
+		assert.Equal(t, 4, bot.Rounds[0].Penalty.Count)
+	}
+
+	// at most one bot goes out first each round
+	for r := range report.Bots[0].Rounds {
+		assert.LessOrEqual(t, report.Bots[0].Rounds[r].GoOutRate+report.Bots[1].Rounds[r].GoOutRate, 1.0)
+	}
+
+	// the same seeds give the same report, however many games are played at once
+	config.Concurrency = 1
+	again, err := Run(config)
+
+	require.NoError(t, err)
+	assert.Equal(t, report, again)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteReport(&out, report))
+	assert.Contains(t, out.String(), "played 4 games with seeds 10 to 13")
+	assert.Contains(t, out.String(), "bigbrainbot")
+}
+
+func TestSeats(t *testing.T) {
+
+	games, err := Play(Config{Bots: table, Games: 2, Seed: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1}, games[0].Seats)
+	assert.Equal(t, []int{1, 0}, games[1].Seats)
+	assert.Equal(t, []string{"bigbrainbot", "grugbot"}, games[1].Result.Players)
+}
+
+func TestRules(t *testing.T) {
+
+	rules := game.OfficialRules
+	rules.FirstRound = 11
+
+	report, err := Run(Config{Bots: table, Games: 1, Seed: 1, Rules: &rules})
+
+	require.NoError(t, err)
+	require.Len(t, report.Bots[0].Rounds, 3)
+	assert.Equal(t, 11, report.Bots[0].Rounds[0].Round)
+}
+
+// discards a card that it was never dealt
+type cheatBot struct{}
+
+func (cheatBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	return bots.DrawResponse{Action: bots.ActionDraw, Stack: bots.StackDeck}, nil
+}
+
+func (cheatBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	return bots.DiscardResponse{Action: bots.ActionDiscard, Card: "99-Z"}, nil
+}
+
+func (cheatBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	return bots.ScoreResponse{Action: bots.ActionScore}, nil
+}
+
+func TestRunErrors(t *testing.T) {
+
+	_, err := Run(Config{Bots: table[:1], Games: 1})
+	assert.Error(t, err)
+
+	_, err = Run(Config{Bots: table, Games: 0})
+	assert.Error(t, err)
+
+	report, err := Run(Config{
+		Bots:  []tournament.Entrant{table[0], {Name: "cheatbot", NewBot: func() bots.Bot { return cheatBot{} }}},
+		Games: 2,
+		Seed:  1,
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 2, report.Failed)
+	assert.Zero(t, report.Bots[0].Games)
+}
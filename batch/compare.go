@@ -0,0 +1,325 @@
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/engine"
+	"github.com/timtatt/fivecrowns/tournament"
+)
+
+// Compare tells whether one bot scores lower than another, or whether the difference is noise
+// every seed is played as a pair of games so both bots are dealt the same cards:
+// head to head the bots swap seats for the second game, otherwise each bot takes the
+// same seat against the same opponents
+//
+// the pairs are looked at every Step pairs, and the comparison stops at the first look which
+// reaches a conclusion. looks before the last use the stricter EarlyAlpha (a haybittle-peto
+// boundary) so peeking at the results keeps the overall false positive rate close to Alpha
+
+type CompareConfig struct {
+	A tournament.Entrant
+	B tournament.Entrant
+	// the rest of the table, when empty the bots play each other
+	Opponents []tournament.Entrant
+	Seed      uint64
+	// most pairs to play, fewer when the comparison stops early
+	MaxPairs int
+	// pairs played between each look at the results
+	Step int
+	// chance of calling a difference when there is none
+	Alpha float64
+	// the stricter threshold for stopping at a look before the last
+	EarlyAlpha float64
+	// differences in mean penalty per round smaller than this are not worth telling apart,
+	// so the comparison stops once the difference is known to be smaller. zero never stops for this
+	Margin float64
+	// number of games to play at the same time. defaults to the number of CPUs
+	Concurrency int
+	// the rules every game is played with, nil for the house rules
+	Rules *game.RuleSet
+}
+
+var DefaultCompareConfig = CompareConfig{
+	Seed:       1,
+	MaxPairs:   2000,
+	Step:       100,
+	Alpha:      0.05,
+	EarlyAlpha: 0.001,
+	Margin:     0.25,
+}
+
+type Verdict string
+
+const (
+	VerdictABetter Verdict = "a-better"
+	VerdictBBetter Verdict = "b-better"
+	// the difference is smaller than the margin
+	VerdictEquivalent   Verdict = "equivalent"
+	VerdictInconclusive Verdict = "inconclusive"
+)
+
+type Comparison struct {
+	A string `json:"a"`
+	B string `json:"b"`
+	// pairs which were played to the end, failed pairs are left out of the stats
+	Pairs     int    `json:"pairs"`
+	Failed    int    `json:"failed"`
+	MaxPairs  int    `json:"maxPairs"`
+	FirstSeed uint64 `json:"firstSeed"`
+	LastSeed  uint64 `json:"lastSeed"`
+	// mean penalty per round of each bot
+	MeanA float64 `json:"meanA"`
+	MeanB float64 `json:"meanB"`
+	// penalty per round of a minus b for every pair, negative when a scores lower
+	Difference Summary `json:"difference"`
+	// confidence interval of the mean difference at 1 - alpha
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+	// two sided p value of the mean difference being zero
+	PValue       float64 `json:"pValue"`
+	Alpha        float64 `json:"alpha"`
+	Verdict      Verdict `json:"verdict"`
+	StoppedEarly bool    `json:"stoppedEarly"`
+}
+
+// a pair of games played with the same seed
+type pair struct {
+	// penalty per round of each bot across both games
+	a, b float64
+	err  error
+}
+
+// plays pairs of games until the difference between the bots is clear, or the pairs run out
+// pairs which fail are counted in the comparison and their errors returned
+func Compare(config CompareConfig) (Comparison, error) {
+
+	if players := len(config.Opponents) + 2; players < engine.MinPlayers || players > engine.MaxPlayers {
+		return Comparison{}, fmt.Errorf("invalid number of players: %d", players)
+	} else if config.MaxPairs < 1 {
+		return Comparison{}, fmt.Errorf("invalid number of pairs: %d", config.MaxPairs)
+	} else if config.Alpha <= 0 || config.Alpha >= 1 {
+		return Comparison{}, fmt.Errorf("invalid alpha: %v", config.Alpha)
+	} else if config.EarlyAlpha < 0 || config.EarlyAlpha > config.Alpha {
+		return Comparison{}, fmt.Errorf("invalid early alpha: %v", config.EarlyAlpha)
+	}
+
+	if config.Step < 1 || config.Step > config.MaxPairs {
+		config.Step = config.MaxPairs
+	}
+
+	if config.Concurrency < 1 {
+		config.Concurrency = runtime.NumCPU()
+	}
+
+	cmp := Comparison{
+		A:         config.A.Name,
+		B:         config.B.Name,
+		MaxPairs:  config.MaxPairs,
+		FirstSeed: config.Seed,
+		Alpha:     config.Alpha,
+		Verdict:   VerdictInconclusive,
+	}
+
+	var a, b Summary
+	var errs error
+	played := 0
+
+	for played < config.MaxPairs {
+		pairs := playPairs(config, played, min(config.Step, config.MaxPairs-played))
+		played += len(pairs)
+
+		// added in seed order, so the same seeds always give the same comparison
+		for _, p := range pairs {
+			if p.err != nil {
+				cmp.Failed += 1
+				errs = errors.Join(errs, p.err)
+				continue
+			}
+
+			a.Add(p.a)
+			b.Add(p.b)
+			cmp.Difference.Add(p.a - p.b)
+		}
+
+		last := played == config.MaxPairs
+		alpha := config.Alpha
+		if !last {
+			alpha = config.EarlyAlpha
+		}
+
+		if verdict := test(cmp.Difference, alpha, config.Margin); verdict != VerdictInconclusive {
+			cmp.Verdict = verdict
+			cmp.StoppedEarly = !last
+			break
+		}
+	}
+
+	cmp.Pairs = cmp.Difference.Count
+	cmp.LastSeed = config.Seed + uint64(max(played-1, 0))
+	cmp.MeanA = a.Mean
+	cmp.MeanB = b.Mean
+	cmp.Low, cmp.High = interval(cmp.Difference, config.Alpha)
+	cmp.PValue = pValue(cmp.Difference)
+
+	return cmp, errs
+}
+
+// plays the pairs with seeds from Seed+first concurrently, returning them in seed order
+func playPairs(config CompareConfig, first int, count int) []pair {
+	pairs := make([]pair, count)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, config.Concurrency)
+
+	for i := range pairs {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(p *pair) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			*p = playPair(config, config.Seed+uint64(first+i))
+		}(&pairs[i])
+	}
+
+	wg.Wait()
+
+	return pairs
+}
+
+// a table of bots with the seats of a and b, -1 when the bot is not at the table
+type seating struct {
+	table []tournament.Entrant
+	a, b  int
+}
+
+func playPair(config CompareConfig, seed uint64) pair {
+	seatings := []seating{
+		{table: []tournament.Entrant{config.A, config.B}, a: 0, b: 1},
+		{table: []tournament.Entrant{config.B, config.A}, a: 1, b: 0},
+	}
+
+	if len(config.Opponents) > 0 {
+		seatings = []seating{
+			{table: append([]tournament.Entrant{config.A}, config.Opponents...), a: 0, b: -1},
+			{table: append([]tournament.Entrant{config.B}, config.Opponents...), a: -1, b: 0},
+		}
+	}
+
+	// total penalty and rounds played by each bot
+	var a, b, aRounds, bRounds int
+
+	for _, s := range seatings {
+		res, err := play(s.table, config.Rules, seed)
+
+		if err != nil {
+			return pair{err: err}
+		}
+
+		for _, round := range res.Rounds {
+			if s.a >= 0 {
+				a += round.Scores[s.a]
+				aRounds += 1
+			}
+
+			if s.b >= 0 {
+				b += round.Scores[s.b]
+				bRounds += 1
+			}
+		}
+	}
+
+	return pair{a: float64(a) / float64(aRounds), b: float64(b) / float64(bRounds)}
+}
+
+// decides the comparison at the given significance
+func test(diff Summary, alpha float64, margin float64) Verdict {
+	if diff.Count < 2 || alpha <= 0 {
+		return VerdictInconclusive
+	}
+
+	low, high := interval(diff, alpha)
+
+	switch {
+	case pValue(diff) < alpha && diff.Mean < 0:
+		return VerdictABetter
+	case pValue(diff) < alpha && diff.Mean > 0:
+		return VerdictBBetter
+	case margin > 0 && low > -margin && high < margin:
+		return VerdictEquivalent
+	}
+
+	return VerdictInconclusive
+}
+
+// normal confidence interval of the mean at 1 - alpha
+func interval(s Summary, alpha float64) (float64, float64) {
+	z := math.Sqrt2 * math.Erfinv(1-alpha)
+	e := z * stdErr(s)
+
+	return s.Mean - e, s.Mean + e
+}
+
+// two sided p value of the mean being zero, from the normal approximation
+func pValue(s Summary) float64 {
+	se := stdErr(s)
+
+	if se == 0 {
+		if s.Mean == 0 || s.Count < 2 {
+			return 1
+		}
+
+		return 0
+	}
+
+	return math.Erfc(math.Abs(s.Mean/se) / math.Sqrt2)
+}
+
+// zero until there are two values, like the variance
+func stdErr(s Summary) float64 {
+	if s.Count < 2 {
+		return 0
+	}
+
+	return math.Sqrt(s.Variance / float64(s.Count))
+}
+
+// writes the mean of each bot, the difference between them and the verdict
+func WriteComparison(w io.Writer, cmp Comparison) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "played %d of %d pairs with seeds %d to %d, %d failed\n\n", cmp.Pairs+cmp.Failed, cmp.MaxPairs, cmp.FirstSeed, cmp.LastSeed, cmp.Failed)
+	fmt.Fprintln(tw, "bot\tmean penalty/round")
+	fmt.Fprintf(tw, "%s\t%.2f\n", cmp.A, cmp.MeanA)
+	fmt.Fprintf(tw, "%s\t%.2f\n\n", cmp.B, cmp.MeanB)
+
+	fmt.Fprintf(tw, "difference\t%+.3f\n", cmp.Difference.Mean)
+	fmt.Fprintf(tw, "%g%% interval\t%+.3f to %+.3f\n", (1-cmp.Alpha)*100, cmp.Low, cmp.High)
+	fmt.Fprintf(tw, "p value\t%.4f\n", cmp.PValue)
+
+	verdict := "no conclusion"
+	switch cmp.Verdict {
+	case VerdictABetter:
+		verdict = fmt.Sprintf("%s scores lower", cmp.A)
+	case VerdictBBetter:
+		verdict = fmt.Sprintf("%s scores lower", cmp.B)
+	case VerdictEquivalent:
+		verdict = "no meaningful difference"
+	}
+
+	if cmp.StoppedEarly {
+		verdict += ", stopped early"
+	}
+
+	fmt.Fprintf(tw, "verdict\t%s\n", verdict)
+
+	return tw.Flush()
+}
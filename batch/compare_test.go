@@ -0,0 +1,135 @@
+package batch
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/tournament"
+)
+
+func TestCompare(t *testing.T) {
+
+	config := DefaultCompareConfig
+	config.A = table[0]
+	config.B = table[1]
+	config.Seed = 5
+	config.MaxPairs = 6
+	config.Step = 3
+
+	cmp, err := Compare(config)
+
+	require.NoError(t, err)
+	assert.Equal(t, "grugbot", cmp.A)
+	assert.Equal(t, uint64(5), cmp.FirstSeed)
+	assert.Equal(t, cmp.FirstSeed+uint64(cmp.Pairs-1), cmp.LastSeed)
+	assert.InDelta(t, cmp.MeanA-cmp.MeanB, cmp.Difference.Mean, 1e-9)
+	assert.LessOrEqual(t, cmp.Low, cmp.Difference.Mean)
+	assert.GreaterOrEqual(t, cmp.High, cmp.Difference.Mean)
+
+	// the same seeds give the same comparison, however many games are played at once
+	config.Concurrency = 1
+	again, err := Compare(config)
+
+	require.NoError(t, err)
+	assert.Equal(t, cmp, again)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteComparison(&out, cmp))
+	assert.Contains(t, out.String(), "with seeds 5 to")
+	assert.Contains(t, out.String(), "verdict")
+}
+
+func TestCompareSameBot(t *testing.T) {
+
+	cases := map[string][]tournament.Entrant{
+		"head to head":   nil,
+		"with opponents": table[1:],
+	}
+
+	for name, opponents := range cases {
+		t.Run(name, func(t *testing.T) {
+			config := DefaultCompareConfig
+			config.A = table[0]
+			config.B = table[0]
+			config.Opponents = opponents
+			config.MaxPairs = 10
+			config.Step = 2
+
+			cmp, err := Compare(config)
+
+			// both bots are dealt the same cards, so every pair plays out the same
+			require.NoError(t, err)
+			assert.Equal(t, VerdictEquivalent, cmp.Verdict)
+			assert.True(t, cmp.StoppedEarly)
+			assert.Equal(t, 2, cmp.Pairs)
+			assert.Zero(t, cmp.Difference.Mean)
+			assert.Equal(t, 1.0, cmp.PValue)
+		})
+	}
+}
+
+func TestVerdict(t *testing.T) {
+
+	cases := []struct {
+		Name    string
+		Values  []float64
+		Margin  float64
+		Verdict Verdict
+	}{
+		{Name: "a better", Values: []float64{-2, -3, -2.5, -1.5, -2, -3}, Verdict: VerdictABetter},
+		{Name: "b better", Values: []float64{2, 3, 2.5, 1.5, 2, 3}, Verdict: VerdictBBetter},
+		{Name: "equivalent", Values: []float64{0.01, -0.01, 0.02, -0.02}, Margin: 0.25, Verdict: VerdictEquivalent},
+		{Name: "no margin", Values: []float64{0.01, -0.01, 0.02, -0.02}, Verdict: VerdictInconclusive},
+		{Name: "noise", Values: []float64{5, -4, 3, -6, 2}, Margin: 0.25, Verdict: VerdictInconclusive},
+		{Name: "one pair", Values: []float64{-3}, Verdict: VerdictInconclusive},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var diff Summary
+
+			for _, value := range tc.Values {
+				diff.Add(value)
+			}
+
+			assert.Equal(t, tc.Verdict, test(diff, 0.05, tc.Margin))
+		})
+	}
+}
+
+func TestCompareErrors(t *testing.T) {
+
+	cases := map[string]func(*CompareConfig){
+		"players":     func(c *CompareConfig) { c.Opponents = make([]tournament.Entrant, 7) },
+		"pairs":       func(c *CompareConfig) { c.MaxPairs = 0 },
+		"alpha":       func(c *CompareConfig) { c.Alpha = 1 },
+		"early alpha": func(c *CompareConfig) { c.EarlyAlpha = 0.1 },
+	}
+
+	for name, modify := range cases {
+		t.Run(name, func(t *testing.T) {
+			config := DefaultCompareConfig
+			config.A = table[0]
+			config.B = table[1]
+			modify(&config)
+
+			_, err := Compare(config)
+			assert.Error(t, err)
+		})
+	}
+
+	config := DefaultCompareConfig
+	config.A = table[0]
+	config.B = tournament.Entrant{Name: "cheatbot", NewBot: func() bots.Bot { return cheatBot{} }}
+	config.MaxPairs = 2
+
+	cmp, err := Compare(config)
+
+	assert.Error(t, err)
+	assert.Equal(t, 2, cmp.Failed)
+	assert.Zero(t, cmp.Pairs)
+	assert.Equal(t, VerdictInconclusive, cmp.Verdict)
+}
@@ -0,0 +1,24 @@
+package batch
+
+// Summary keeps the mean and variance of a stream of values without holding on to them
+type Summary struct {
+	Count int     `json:"count"`
+	Mean  float64 `json:"mean"`
+	// the sample variance, zero until there are two values
+	Variance float64 `json:"variance"`
+
+	// sum of squared differences from the mean
+	m2 float64
+}
+
+func (s *Summary) Add(value float64) {
+	s.Count += 1
+
+	delta := value - s.Mean
+	s.Mean += delta / float64(s.Count)
+	s.m2 += delta * (value - s.Mean)
+
+	if s.Count > 1 {
+		s.Variance = s.m2 / float64(s.Count-1)
+	}
+}
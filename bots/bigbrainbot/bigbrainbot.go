@@ -2,12 +2,12 @@ package bigbrainbot
 
 import (
 	"errors"
-	"fmt"
 	"log/slog"
 	"slices"
 
 	"github.com/timtatt/fivecrowns/bots"
 	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/bots/strategy"
 	"github.com/timtatt/fivecrowns/game"
 )
 
@@ -19,40 +19,37 @@ func NewBigBrainBot() bots.Bot {
 
 func (b *bigBrainBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
 
-	calculation, err := grugbot.Calculate(req)
-
-	if err != nil {
-		return bots.ScoreResponse{}, fmt.Errorf("cannot calculate response: %w", err)
-	}
+	calculation := Calculate(req)
 
 	return bots.ScoreResponse{
 		Action:    req.Action,
-		Flop:      game.CanFlop(calculation.Sequences),
+		Flop:      req.RuleSet().CanFlop(calculation.Sequences, req.Round),
 		Sequences: game.EncodeSequences(calculation.Sequences),
 	}, nil
 }
 
 func (b *bigBrainBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
 
-	// add the discard to the hand and determine if it gets added to a valid sequence
+	// nothing to pick up at the start of a round
+	if len(req.Discard) == 0 {
+		return bots.DrawResponse{
+			Action: req.Action,
+			Stack:  bots.StackDeck,
+		}, nil
+	}
 
-	topCard, err := game.DecodeCard(req.Discard[0])
+	// add the discard to the hand and determine if it gets added to a valid sequence
 
-	if err != nil {
-		return bots.DrawResponse{}, fmt.Errorf("unable to decode discard card: %w", err)
-	}
+	topCard := req.Discard[0]
 
-	hypothetical, err := Calculate(bots.BotRequest{
+	hypothetical := Calculate(bots.BotRequest{
 		Action:  req.Action,
-		Hand:    append(req.Hand, req.Discard[0]),
+		Hand:    append(slices.Clone(req.Hand), topCard),
 		Round:   req.Round,
 		Discard: req.Discard,
+		Rules:   req.Rules,
 	})
 
-	if err != nil {
-		return bots.DrawResponse{}, fmt.Errorf("unable to hypothesise discard score: %w", err)
-	}
-
 	// determine if the topCard has been used in a sequence
 	// goes in reverse and checks if there is an invalid sequence with only the topCard
 	for i := len(hypothetical.Sequences) - 1; i >= 0; i-- {
@@ -78,14 +75,17 @@ func (b *bigBrainBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
 
 func (b *bigBrainBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
 
-	calculation, err := Calculate(req)
-
-	if err != nil {
-		return bots.DiscardResponse{}, fmt.Errorf("unable to calculate score: %w", err)
-	}
+	calculation := Calculate(req)
 
 	// determine which card is the highest one that is not in a valid sequence
-	worstCard := grugbot.WorstCard(req.Round, calculation.Sequences, req.LastTurn)
+	worstCard := grugbot.WorstCard(req.RuleSet(), req.Round, calculation.Sequences, req.LastTurn)
+
+	// when an opponent is likely to go out soon, shed the most expensive card rather than building sequences
+	if strategy.DamageControl(req) {
+		if shed, ok := strategy.Shed(req.RuleSet(), req.Round, calculation.Sequences, strategy.FlopChance(req)); ok {
+			worstCard = grugbot.CardAndLocation(shed)
+		}
+	}
 
 	slog.Info("worst card detected", "card", worstCard)
 
@@ -101,7 +101,7 @@ func (b *bigBrainBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error)
 	}
 
 	return bots.DiscardResponse{
-		Flop:      game.CanFlop(calculation.Sequences),
+		Flop:      req.RuleSet().CanFlop(calculation.Sequences, req.Round),
 		Sequences: game.EncodeSequences(calculation.Sequences),
 		Action:    bots.ActionDiscard,
 		Card:      worstCard.Card.Encode(),
@@ -114,27 +114,16 @@ type Calculation struct {
 	Hand      []game.Card
 }
 
-func Calculate(req bots.BotRequest) (Calculation, error) {
-	hand, err := game.DecodeCards(req.Hand)
-
-	if err != nil {
-		return Calculation{}, fmt.Errorf("unable to decode cards: %w", err)
-	}
-
-	// sort the cards by suite and number
-	slices.SortFunc(hand, game.CompareCard)
-
-	// find all possible sequences in the hand
-	seqs := grugbot.FindSequences(req.Round, hand)
-
-	slog.Info("calculated possible sequences", "seqs", game.EncodeSequences(seqs))
+// arranges the hand into the sequences which leave the lowest penalty, splitting sequences where it helps
+func Calculate(req bots.BotRequest) Calculation {
+	rules := req.RuleSet()
+	arrangement := rules.OptimalArrangement(req.Hand, req.Round)
+	seqs := arrangement.Hand()
 
-	// filter out sequences if they have cards that have been used twiced
-	// use the wilds to build more sequences
-	seqs = grugbot.FilterSequences(req.Round, hand, seqs)
+	slog.Debug("calculated optimal sequences", "seqs", game.EncodeSequences(seqs), "penalty", arrangement.Penalty)
 
 	return Calculation{
-		Flop:      game.CanFlop(seqs),
+		Flop:      rules.CanFlop(seqs, req.Round),
 		Sequences: seqs,
-	}, nil
+	}
 }
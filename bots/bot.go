@@ -1,19 +1,54 @@
 package bots
 
+import "github.com/timtatt/fivecrowns/game"
+
 type Bot interface {
 	Draw(req BotRequest) (DrawResponse, error)
 	Discard(req BotRequest) (DiscardResponse, error)
 	Score(req BotRequest) (ScoreResponse, error)
 }
 
+// cards are sent as codes eg. "10-R", and decoded before the request reaches the bot
 type BotRequest struct {
-	Discard     []string `json:"discard"`
-	Hand        []string `json:"hand"`
-	Action      Action   `json:"action"`
-	NewestCard  string   `json:"newestCard"`
-	PlayerCount int      `json:"playerCount"`
-	Round       int      `json:"round"`
-	LastTurn    bool     `json:"lastTurn"`
+	// the discard pile, top card first
+	Discard []game.Card `json:"discard"`
+	Hand    []game.Card `json:"hand"`
+	Action  Action      `json:"action"`
+	// the card just drawn, nil on draw and score requests
+	NewestCard  game.Card `json:"newestCard"`
+	PlayerCount int       `json:"playerCount"`
+	Round       int       `json:"round"`
+	LastTurn    bool      `json:"lastTurn"`
+	// turn number within the round, starting at 1
+	Turn int `json:"turn"`
+	// number of cards left in the deck
+	DeckCount int `json:"deckCount"`
+	// every turn the other players have taken this round, oldest first
+	History []OpponentTurn `json:"history"`
+	// the rules the match is played with. requests without rules are played with the house rules
+	Rules *game.RuleSet `json:"rules,omitempty"`
+}
+
+// returns the rules the match is played with
+func (req BotRequest) RuleSet() game.RuleSet {
+	if req.Rules == nil {
+		return game.HouseRules
+	}
+
+	return *req.Rules
+}
+
+// OpponentTurn is what every player at the table sees of another player's turn
+type OpponentTurn struct {
+	// seat relative to the player receiving the request
+	// 1 is the opponent to the left, who plays next and can pick up their discards
+	Seat int `json:"seat"`
+	// turn number within the round, starting at 1
+	Turn  int   `json:"turn"`
+	Stack Stack `json:"stack"`
+	// the card picked up from the discard pile. nil when drawn from the deck, as those cards are hidden
+	Drawn   game.Card `json:"drawn"`
+	Discard game.Card `json:"discard"`
 }
 
 type Action string
@@ -1 +1,217 @@
 package galaxybrainbot
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+// Galaxybrainbot reads the discard pile to work out which cards are still in the deck
+// it draws and discards to minimise the expected penalty of its hand, counting partial sequences
+// by the chance of drawing a card to complete them
+// the bot keeps state between turns, so each match needs its own bot
+
+type galaxyBrainBot struct {
+	mu      sync.Mutex
+	tracker *tracker
+}
+
+func NewGalaxyBrainBot() bots.Bot {
+	return &galaxyBrainBot{
+		tracker: newTracker(),
+	}
+}
+
+// number of future draws a partial sequence has to be completed in
+const horizon = 3
+
+// the penalty for discarding a card the left neighbour is sure to want
+const handOffCost = 5
+
+func (b *galaxyBrainBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+
+	calculation := grugbot.Calculate(req)
+
+	return bots.ScoreResponse{
+		Action:    req.Action,
+		Flop:      req.RuleSet().CanFlop(calculation.Sequences, req.Round),
+		Sequences: game.EncodeSequences(calculation.Sequences),
+	}, nil
+}
+
+func (b *galaxyBrainBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hand, pile := req.Hand, req.Discard
+
+	rules := req.RuleSet()
+	b.tracker.observe(req.Round, pile, req.PlayerCount)
+
+	if len(pile) == 0 {
+		return bots.DrawResponse{
+			Action: req.Action,
+			Stack:  bots.StackDeck,
+		}, nil
+	}
+
+	est := b.tracker.estimate(rules, req.Round, hand, pile, req.PlayerCount, req.DeckCount)
+	draws := remainingDraws(req.LastTurn)
+
+	// the expected penalty after taking the top of the discard pile
+	fromDiscard := evaluateDraw(rules, req.Round, hand, pile[0], est, draws)
+
+	fromDeck := evaluateDeck(rules, req.Round, hand, est, draws)
+
+	slog.Info("expected penalties", "discard", fromDiscard, "deck", fromDeck)
+
+	stack := bots.StackDeck
+	if len(est.odds) == 0 || fromDiscard <= fromDeck {
+		stack = bots.StackDiscard
+	}
+
+	return bots.DrawResponse{
+		Action: req.Action,
+		Stack:  stack,
+	}, nil
+}
+
+func (b *galaxyBrainBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hand, pile := req.Hand, req.Discard
+
+	if len(hand) == 0 {
+		return bots.DiscardResponse{}, errors.New("cannot discard from an empty hand")
+	}
+
+	rules := req.RuleSet()
+	est := b.tracker.estimate(rules, req.Round, hand, pile, req.PlayerCount, req.DeckCount)
+	draws := remainingDraws(req.LastTurn)
+	wants := neighbourWants(req.Round, req.History)
+
+	var best game.Card
+	var bestSeqs [][]game.Card
+	bestValue := math.Inf(1)
+
+	// try discarding each distinct card, keeping the hand with the lowest expected penalty
+	// cards the left neighbour is likely to pick up cost extra. ties are broken by discarding the highest card
+	for _, card := range distinct(hand) {
+		rest := remove(hand, card)
+		seqs := arrange(rules, req.Round, rest)
+		value := evaluate(rules, req.Round, seqs, est, draws) + handOffCost*wants[card]
+
+		if value < bestValue || (value == bestValue && rules.ScoreCard(card, req.Round) > rules.ScoreCard(best, req.Round)) {
+			best = card
+			bestSeqs = seqs
+			bestValue = value
+		}
+	}
+
+	slog.Info("discarding card", "card", best.Encode(), "expected", bestValue)
+
+	b.tracker.discarded(req.Round, pile, best)
+
+	return bots.DiscardResponse{
+		Flop:      rules.CanFlop(bestSeqs, req.Round),
+		Sequences: game.EncodeSequences(bestSeqs),
+		Action:    bots.ActionDiscard,
+		Card:      best.Encode(),
+	}, nil
+}
+
+// the expected penalty after drawing an unknown card from the deck
+// the cards are summed in a fixed order, so the same request always gets the same response
+func evaluateDeck(rules game.RuleSet, round int, hand []game.Card, est estimate, draws int) float64 {
+	value := 0.0
+	for _, card := range est.cards {
+		value += est.odds[card] * evaluateDraw(rules, round, hand, card, est, draws)
+	}
+
+	return value
+}
+
+// estimates the penalty of a hand after adding a card and discarding the worst one
+func evaluateDraw(rules game.RuleSet, round int, hand []game.Card, card game.Card, est estimate, draws int) float64 {
+	seqs := arrange(rules, round, append(slices.Clone(hand), card))
+
+	worst := grugbot.WorstCard(rules, round, seqs, draws == 0)
+
+	seqs[worst.SequenceIdx] = slices.Delete(slices.Clone(seqs[worst.SequenceIdx]), worst.CardIdx, worst.CardIdx+1)
+
+	return evaluate(rules, round, seqs, est, draws)
+}
+
+// the penalty of the hand, less the chance of completing each partial sequence
+func evaluate(rules game.RuleSet, round int, seqs [][]game.Card, est estimate, draws int) float64 {
+	value := float64(rules.ScorePenalty(seqs, round))
+
+	if draws == 0 {
+		return value
+	}
+
+	for _, seq := range seqs {
+		if len(seq) < 2 || rules.ValidateSequence(seq, round) == nil {
+			continue
+		}
+
+		// the missing cards which would complete the sequence
+		outs := make([]game.Card, 0)
+		for _, card := range est.cards {
+			if rules.ValidateSequence(append(slices.Clone(seq), card), round) == nil {
+				outs = append(outs, card)
+			}
+		}
+
+		value -= est.chance(outs, draws) * float64(rules.ScoreSequence(seq, round))
+	}
+
+	return value
+}
+
+// arranges the hand into the sequences which leave the lowest penalty
+// the leftover cards are grouped into partial sequences the same way as grugbot
+func arrange(rules game.RuleSet, round int, hand []game.Card) [][]game.Card {
+	arrangement := rules.OptimalArrangement(hand, round)
+
+	if len(arrangement.Leftover) == 0 {
+		return arrangement.Sequences
+	}
+
+	leftover := slices.Clone(arrangement.Leftover)
+	slices.SortFunc(leftover, game.CompareCard)
+
+	partials := grugbot.FilterSequences(rules, round, leftover, grugbot.FindSequences(round, leftover))
+
+	return append(arrangement.Sequences, partials...)
+}
+
+// the bot can no longer complete sequences on its last turn
+func remainingDraws(lastTurn bool) int {
+	if lastTurn {
+		return 0
+	}
+
+	return horizon
+}
+
+func distinct(cards []game.Card) []game.Card {
+	out := slices.Clone(cards)
+	slices.SortFunc(out, game.CompareCard)
+
+	return slices.Compact(out)
+}
+
+// returns the cards without one copy of the card
+func remove(cards []game.Card, card game.Card) []game.Card {
+	idx := slices.Index(cards, card)
+
+	return slices.Delete(slices.Clone(cards), idx, idx+1)
+}
@@ -0,0 +1,303 @@
+package galaxybrainbot
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/engine"
+)
+
+func cards(t *testing.T, encoded string) []game.Card {
+	if encoded == "" {
+		return []game.Card{}
+	}
+
+	out, err := game.DecodeCards(strings.Split(encoded, ":"))
+	require.NoError(t, err)
+
+	return out
+}
+
+func TestTrackerObserve(t *testing.T) {
+
+	cases := []struct {
+		Name     string
+		Previous string
+		Pile     string
+		Taken    string
+	}{
+		{
+			Name:     "opponent discards",
+			Previous: "9-R:4-B",
+			Pile:     "7-X:9-R:4-B",
+			Taken:    "",
+		},
+		{
+			Name:     "opponent picks up",
+			Previous: "9-R:4-B",
+			Pile:     "7-X:4-B",
+			Taken:    "9-R",
+		},
+		{
+			Name:     "pile reshuffled",
+			Previous: "9-R:4-B:5-Y:6-Y",
+			Pile:     "7-X",
+			Taken:    "",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			tr := newTracker()
+
+			previous := cards(t, c.Previous)
+			tr.discarded(5, previous[1:], previous[0])
+			tr.observe(5, cards(t, c.Pile), 2)
+
+			taken := make([]game.Card, 0)
+			for card, count := range tr.taken {
+				for range count {
+					taken = append(taken, card)
+				}
+			}
+
+			assert.ElementsMatch(t, cards(t, c.Taken), taken)
+		})
+	}
+}
+
+func TestTrackerPutBack(t *testing.T) {
+	tr := newTracker()
+
+	// the opponent picks up the 9-R, then discards it on their next turn
+	tr.discarded(5, cards(t, "4-B"), game.Card{Number: 9, Suite: game.SuiteRed})
+	tr.observe(5, cards(t, "7-X:4-B"), 2)
+	tr.discarded(5, cards(t, "7-X:4-B"), game.Card{Number: 3, Suite: game.SuiteRed})
+	tr.observe(5, cards(t, "9-R:3-R:7-X:4-B"), 2)
+
+	assert.Zero(t, tr.taken[game.Card{Number: 9, Suite: game.SuiteRed}])
+}
+
+func TestTrackerEstimate(t *testing.T) {
+	tr := newTracker()
+
+	hand := cards(t, "9-R:9-R:5-B")
+	pile := cards(t, "*:5-B")
+
+	est := tr.estimate(game.HouseRules, 3, hand, pile, 2, 0)
+
+	// both copies of the 9-R and 5-B have been seen
+	assert.NotContains(t, est.odds, game.Card{Number: 9, Suite: game.SuiteRed})
+	assert.NotContains(t, est.odds, game.Card{Number: 5, Suite: game.SuiteBlue})
+
+	total := 0.0
+	for _, chance := range est.odds {
+		total += chance
+	}
+	assert.InDelta(t, 1, total, 1e-9)
+
+	// 111 unseen cards, 3 of which are in the opponent's hand
+	assert.InDelta(t, 108, est.deckSize, 1e-9)
+
+	// jokers have more unseen copies than any other card
+	joker := est.inDeck[game.CardJoker]
+	assert.Greater(t, joker, est.inDeck[game.Card{Number: 7, Suite: game.SuiteBlue}])
+	assert.LessOrEqual(t, joker, 1.0)
+}
+
+func TestGalaxyBrainBotDraw(t *testing.T) {
+
+	cases := []struct {
+		Hand     string
+		Discard  string
+		Round    int
+		Expected bots.Stack
+	}{
+		{
+			// completes a set
+			Hand:     "9-R:9-B:4-X:12-Y",
+			Discard:  "9-Y",
+			Round:    4,
+			Expected: bots.StackDiscard,
+		},
+		{
+			// a wild is always worth taking
+			Hand:     "9-R:9-B:6-X:12-Y",
+			Discard:  "*",
+			Round:    4,
+			Expected: bots.StackDiscard,
+		},
+		{
+			// a high card which does not fit the hand
+			Hand:     "9-R:9-B:10-B:4-X",
+			Discard:  "13-Y",
+			Round:    4,
+			Expected: bots.StackDeck,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Hand, func(t *testing.T) {
+			res, err := NewGalaxyBrainBot().Draw(bots.BotRequest{
+				Action:      bots.ActionDraw,
+				Hand:        game.MustDecodeSequence(c.Hand),
+				Discard:     game.MustDecodeSequence(c.Discard),
+				Round:       c.Round,
+				PlayerCount: 2,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, c.Expected, res.Stack)
+		})
+	}
+}
+
+func TestGalaxyBrainBotDiscard(t *testing.T) {
+
+	cases := []struct {
+		Hand     string
+		Round    int
+		Expected string
+		Flop     bool
+	}{
+		{
+			Hand:     "9-R:9-B:9-Y:13-X",
+			Round:    3,
+			Expected: "13-X",
+			Flop:     true,
+		},
+		{
+			// keeps the pair which could become a set
+			Hand:     "9-R:9-B:12-Y:4-X:5-X",
+			Round:    4,
+			Expected: "12-Y",
+		},
+		{
+			Hand:     "3-B:4-B:5-B:6-B:10-R",
+			Round:    7,
+			Expected: "10-R",
+			Flop:     true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Hand, func(t *testing.T) {
+			res, err := NewGalaxyBrainBot().Discard(bots.BotRequest{
+				Action:      bots.ActionDiscard,
+				Hand:        game.MustDecodeSequence(c.Hand),
+				Discard:     game.MustDecodeSequence("7-G"),
+				Round:       c.Round,
+				PlayerCount: 2,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, c.Expected, res.Card)
+			assert.Equal(t, c.Flop, res.Flop)
+		})
+	}
+}
+
+func TestGalaxyBrainBotDeterministic(t *testing.T) {
+
+	req := bots.BotRequest{
+		Hand:        cards(t, "9-R:10-R:5-X:8-R:6-B:8-B:11-R:11-Y:4-Y"),
+		Discard:     cards(t, "12-G:7-Y:3-B"),
+		Round:       9,
+		PlayerCount: 3,
+		DeckCount:   70,
+	}
+
+	est := newTracker().estimate(game.HouseRules, req.Round, req.Hand, req.Discard, req.PlayerCount, req.DeckCount)
+	expected := evaluateDeck(game.HouseRules, req.Round, req.Hand, est, horizon)
+
+	// maps are iterated in a different order every time, so float sums over them are not repeatable
+	for range 20 {
+		again := newTracker().estimate(game.HouseRules, req.Round, req.Hand, req.Discard, req.PlayerCount, req.DeckCount)
+		assert.Equal(t, expected, evaluateDeck(game.HouseRules, req.Round, req.Hand, again, horizon))
+	}
+
+	respond := func() (bots.DrawResponse, bots.DiscardResponse) {
+		bot := NewGalaxyBrainBot()
+
+		draw, err := bot.Draw(req)
+		require.NoError(t, err)
+
+		discardReq := req
+		discardReq.Hand = append(slices.Clone(req.Hand), req.Discard[0])
+		discardReq.Discard = req.Discard[1:]
+
+		discard, err := bot.Discard(discardReq)
+		require.NoError(t, err)
+
+		return draw, discard
+	}
+
+	draw, discard := respond()
+
+	for range 5 {
+		againDraw, againDiscard := respond()
+		assert.Equal(t, draw, againDraw)
+		assert.Equal(t, discard, againDiscard)
+	}
+}
+
+func TestGalaxyBrainBotMatch(t *testing.T) {
+
+	e := engine.NewEngine([]engine.Player{
+		{Name: "galaxybrainbot", Bot: NewGalaxyBrainBot()},
+		{Name: "grugbot", Bot: grugbot.NewGrugBot()},
+		{Name: "galaxybrainbot", Bot: NewGalaxyBrainBot()},
+	}, 1)
+
+	res, err := e.Play()
+
+	require.NoError(t, err)
+	assert.Len(t, res.Rounds, engine.LastRound-engine.FirstRound+1)
+}
+
+func TestNeighbourWants(t *testing.T) {
+
+	wants := neighbourWants(5, []bots.OpponentTurn{
+		{Seat: 1, Turn: 1, Stack: bots.StackDiscard, Drawn: game.MustDecodeCard("9-R"), Discard: game.MustDecodeCard("13-B")},
+		// only the left neighbour can pick up the bot's discards
+		{Seat: 2, Turn: 2, Stack: bots.StackDiscard, Drawn: game.MustDecodeCard("3-G"), Discard: game.MustDecodeCard("4-G")},
+	})
+
+	assert.Equal(t, 0.5, wants[game.Card{Number: 9, Suite: game.SuiteBlue}])
+	assert.Equal(t, 0.5, wants[game.Card{Number: 10, Suite: game.SuiteRed}])
+	assert.Zero(t, wants[game.Card{Number: 12, Suite: game.SuiteRed}])
+	assert.Zero(t, wants[game.Card{Number: 13, Suite: game.SuiteBlue}])
+	assert.Zero(t, wants[game.Card{Number: 3, Suite: game.SuiteGreen}])
+	assert.Equal(t, 1.0, wants[game.CardJoker])
+	assert.Equal(t, 1.0, wants[game.Card{Number: 5, Suite: game.SuiteBlack}])
+}
+
+func TestGalaxyBrainBotAvoidsFeedingNeighbour(t *testing.T) {
+
+	req := bots.BotRequest{
+		Action:      bots.ActionDiscard,
+		Hand:        game.MustDecodeSequence("9-R:9-B:9-Y:12-G:11-X"),
+		Discard:     game.MustDecodeSequence("7-G"),
+		Round:       3,
+		PlayerCount: 3,
+	}
+
+	res, err := NewGalaxyBrainBot().Discard(req)
+	require.NoError(t, err)
+	assert.Equal(t, "12-G", res.Card)
+
+	// the left neighbour has been collecting twelves
+	req.History = []bots.OpponentTurn{
+		{Seat: 1, Turn: 1, Stack: bots.StackDiscard, Drawn: game.MustDecodeCard("12-Y"), Discard: game.MustDecodeCard("4-B")},
+	}
+
+	res, err = NewGalaxyBrainBot().Discard(req)
+	require.NoError(t, err)
+	assert.Equal(t, "11-X", res.Card)
+}
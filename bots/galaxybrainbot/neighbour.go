@@ -0,0 +1,79 @@
+package galaxybrainbot
+
+import (
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+// the opponent to the left plays next and is the only one who can pick up the bot's discard
+// the cards they pick up and throw away hint at the runs and sets they are building
+
+const leftNeighbour = 1
+
+// how much the hint from a single card counts towards the neighbour wanting another card
+const (
+	pickupHint  = 0.5
+	discardHint = 0.25
+)
+
+// estimates how likely the left neighbour is to want each card, between 0 and 1
+// a picked up card suggests they want cards of the same number, or nearby cards of the same suite
+// a discarded card suggests the opposite
+func neighbourWants(round int, history []bots.OpponentTurn) map[game.Card]float64 {
+	wants := make(map[game.Card]float64)
+
+	for _, turn := range history {
+		if turn.Seat != leftNeighbour {
+			continue
+		}
+
+		if turn.Stack == bots.StackDiscard && !turn.Drawn.IsNil() {
+			hint(wants, round, turn.Drawn, pickupHint)
+		}
+
+		if !turn.Discard.IsNil() {
+			hint(wants, round, turn.Discard, -discardHint)
+		}
+	}
+
+	for card, want := range wants {
+		wants[card] = min(max(want, 0), 1)
+	}
+
+	// wilds complete anything
+	for _, card := range deckCards {
+		if card.IsWild(round) {
+			wants[card] = 1
+		}
+	}
+
+	return wants
+}
+
+// adds weight to every card which could share a sequence with the card
+func hint(wants map[game.Card]float64, round int, card game.Card, weight float64) {
+	if card.IsWild(round) {
+		return
+	}
+
+	for _, other := range deckCards {
+		if other.IsWild(round) {
+			continue
+		}
+
+		sameSet := other.Number == card.Number
+		sameRun := other.Suite == card.Suite && abs(other.Number-card.Number) <= 2
+
+		if sameSet || sameRun {
+			wants[other] += weight
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
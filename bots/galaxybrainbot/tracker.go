@@ -0,0 +1,202 @@
+package galaxybrainbot
+
+import (
+	"math"
+	"slices"
+
+	"github.com/timtatt/fivecrowns/game"
+)
+
+// tracker remembers the discard pile between turns to work out where the missing cards are
+// a missing card is one which is not in the hand or the discard pile. it is either in the deck or an opponent's hand
+
+type tracker struct {
+	round int
+	// the discard pile after the bot's last discard, top-most card first
+	pile []game.Card
+	// cards seen being picked up off the discard pile by opponents
+	taken map[game.Card]int
+}
+
+func newTracker() *tracker {
+	return &tracker{
+		taken: make(map[game.Card]int),
+	}
+}
+
+// every distinct card which can be dealt. the rules only change how many copies of each there are
+var deckCards = distinct(game.HouseRules.NewDeck().Cards())
+
+// every distinct card in a deck and the number of copies of it
+func deckCounts(rules game.RuleSet) map[game.Card]int {
+	counts := make(map[game.Card]int)
+
+	for _, card := range rules.NewDeck().Cards() {
+		counts[card] += 1
+	}
+
+	return counts
+}
+
+// updates the tracker with the discard pile at the start of the bot's turn
+// any card which left the pile since the bot's last discard was picked up by an opponent,
+// unless the pile was shuffled back into the deck
+func (t *tracker) observe(round int, pile []game.Card, playerCount int) {
+	if round != t.round {
+		t.round = round
+		t.pile = nil
+		clear(t.taken)
+	}
+
+	if t.pile == nil {
+		return
+	}
+
+	gone := difference(t.pile, pile)
+	added := difference(pile, t.pile)
+
+	// each opponent can pick up at most one card, any more and the pile has been reshuffled
+	if len(gone) <= playerCount-1 {
+		for _, card := range gone {
+			t.taken[card] += 1
+		}
+	}
+
+	// an opponent may have discarded a card they picked up earlier
+	for _, card := range added {
+		if t.taken[card] > 0 {
+			t.taken[card] -= 1
+		}
+	}
+}
+
+// remembers the pile after the bot's discard
+func (t *tracker) discarded(round int, pile []game.Card, card game.Card) {
+	if round != t.round {
+		t.round = round
+		clear(t.taken)
+	}
+
+	t.pile = append([]game.Card{card}, pile...)
+}
+
+// counts the copies of every card which could still be in the deck
+func (t *tracker) unknown(rules game.RuleSet, hand []game.Card, pile []game.Card) map[game.Card]int {
+	counts := deckCounts(rules)
+
+	for card, copies := range counts {
+		counts[card] = copies - t.taken[card]
+	}
+
+	for _, card := range hand {
+		counts[card] -= 1
+	}
+
+	for _, card := range pile {
+		counts[card] -= 1
+	}
+
+	for card, count := range counts {
+		if count <= 0 {
+			delete(counts, card)
+		}
+	}
+
+	return counts
+}
+
+type estimate struct {
+	// every missing card, sorted so sums over them are the same on every run
+	cards []game.Card
+	// the chance of each missing card being the next one drawn from the deck
+	odds map[game.Card]float64
+	// the probability that at least one copy of each missing card is still in the deck
+	inDeck map[game.Card]float64
+	// the expected number of cards left in the deck
+	deckSize float64
+}
+
+// estimates where the missing cards are. every unseen copy is equally likely to be in the deck
+// the rest of the missing cards are spread between the opponents' hands
+// the size of the deck is worked out from the opponents' hands when it is not known
+func (t *tracker) estimate(rules game.RuleSet, round int, hand []game.Card, pile []game.Card, playerCount int, deckCount int) estimate {
+	unknown := t.unknown(rules, hand, pile)
+
+	total := 0
+	for _, count := range unknown {
+		total += count
+	}
+
+	taken := 0
+	for _, count := range t.taken {
+		taken += count
+	}
+
+	e := estimate{
+		odds:   make(map[game.Card]float64, len(unknown)),
+		inDeck: make(map[game.Card]float64, len(unknown)),
+	}
+
+	if total == 0 {
+		return e
+	}
+
+	// opponents hold a full hand between turns, some of which we have seen them pick up
+	hidden := max(round*(max(playerCount, 2)-1)-taken, 0)
+	e.deckSize = math.Max(float64(total-hidden), 0)
+
+	if deckCount > 0 {
+		e.deckSize = math.Min(float64(deckCount), float64(total))
+	}
+
+	// the chance any single unseen copy is in the deck rather than an opponent's hand
+	chance := e.deckSize / float64(total)
+
+	for card, count := range unknown {
+		e.cards = append(e.cards, card)
+		e.odds[card] = float64(count) / float64(total)
+		e.inDeck[card] = 1 - math.Pow(1-chance, float64(count))
+	}
+
+	slices.SortFunc(e.cards, game.CompareCard)
+
+	return e
+}
+
+// the chance of drawing at least one of the cards within a number of draws from the deck
+func (e estimate) chance(cards []game.Card, draws int) float64 {
+	if draws == 0 || e.deckSize == 0 {
+		return 0
+	}
+
+	// the chance a card in the deck is reached within the draws
+	reach := math.Min(float64(draws)/e.deckSize, 1)
+
+	missed := 1.0
+	for _, card := range cards {
+		missed *= 1 - e.inDeck[card]*reach
+	}
+
+	return 1 - missed
+}
+
+// returns the cards in a which are not in b, counting duplicates
+func difference(a, b []game.Card) []game.Card {
+	counts := make(map[game.Card]int)
+
+	for _, card := range b {
+		counts[card] += 1
+	}
+
+	diff := make([]game.Card, 0)
+
+	for _, card := range a {
+		if counts[card] > 0 {
+			counts[card] -= 1
+		} else {
+			diff = append(diff, card)
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,744 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        (unknown)
+// source: bot.proto
+
+package botpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// mirrors bots.BotRequest
+type BotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// name of the bot to ask when the server hosts more than one
+	Bot         string   `protobuf:"bytes,1,opt,name=bot,proto3" json:"bot,omitempty"`
+	Action      string   `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	PlayerCount int32    `protobuf:"varint,3,opt,name=player_count,json=playerCount,proto3" json:"player_count,omitempty"`
+	Round       int32    `protobuf:"varint,4,opt,name=round,proto3" json:"round,omitempty"`
+	LastTurn    bool     `protobuf:"varint,5,opt,name=last_turn,json=lastTurn,proto3" json:"last_turn,omitempty"`
+	Hand        []string `protobuf:"bytes,6,rep,name=hand,proto3" json:"hand,omitempty"`
+	NewestCard  string   `protobuf:"bytes,7,opt,name=newest_card,json=newestCard,proto3" json:"newest_card,omitempty"`
+	// top-most card is at index 0
+	Discard []string `protobuf:"bytes,8,rep,name=discard,proto3" json:"discard,omitempty"`
+	// turns played by the other players this round, oldest first
+	History []*OpponentTurn `protobuf:"bytes,9,rep,name=history,proto3" json:"history,omitempty"`
+	// turn number within the round, starting at 1
+	Turn int32 `protobuf:"varint,10,opt,name=turn,proto3" json:"turn,omitempty"`
+	// number of cards left in the deck
+	DeckCount int32 `protobuf:"varint,11,opt,name=deck_count,json=deckCount,proto3" json:"deck_count,omitempty"`
+	// the rules the match is played with. unset means the house rules
+	Rules *RuleSet `protobuf:"bytes,12,opt,name=rules,proto3" json:"rules,omitempty"`
+}
+
+func (x *BotRequest) Reset() {
+	*x = BotRequest{}
+	mi := &file_bot_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BotRequest) ProtoMessage() {}
+
+func (x *BotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bot_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BotRequest.ProtoReflect.Descriptor instead.
+func (*BotRequest) Descriptor() ([]byte, []int) {
+	return file_bot_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BotRequest) GetBot() string {
+	if x != nil {
+		return x.Bot
+	}
+	return ""
+}
+
+func (x *BotRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *BotRequest) GetPlayerCount() int32 {
+	if x != nil {
+		return x.PlayerCount
+	}
+	return 0
+}
+
+func (x *BotRequest) GetRound() int32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *BotRequest) GetLastTurn() bool {
+	if x != nil {
+		return x.LastTurn
+	}
+	return false
+}
+
+func (x *BotRequest) GetHand() []string {
+	if x != nil {
+		return x.Hand
+	}
+	return nil
+}
+
+func (x *BotRequest) GetNewestCard() string {
+	if x != nil {
+		return x.NewestCard
+	}
+	return ""
+}
+
+func (x *BotRequest) GetDiscard() []string {
+	if x != nil {
+		return x.Discard
+	}
+	return nil
+}
+
+func (x *BotRequest) GetHistory() []*OpponentTurn {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+func (x *BotRequest) GetTurn() int32 {
+	if x != nil {
+		return x.Turn
+	}
+	return 0
+}
+
+func (x *BotRequest) GetDeckCount() int32 {
+	if x != nil {
+		return x.DeckCount
+	}
+	return 0
+}
+
+func (x *BotRequest) GetRules() *RuleSet {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// mirrors game.RuleSet
+type RuleSet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name       string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	JokerValue int32  `protobuf:"varint,2,opt,name=joker_value,json=jokerValue,proto3" json:"joker_value,omitempty"`
+	// 0 scores the round's wild at its face value
+	WildValue         int32 `protobuf:"varint,3,opt,name=wild_value,json=wildValue,proto3" json:"wild_value,omitempty"`
+	MinSequenceLength int32 `protobuf:"varint,4,opt,name=min_sequence_length,json=minSequenceLength,proto3" json:"min_sequence_length,omitempty"`
+	WrapRuns          bool  `protobuf:"varint,5,opt,name=wrap_runs,json=wrapRuns,proto3" json:"wrap_runs,omitempty"`
+	DeckCopies        int32 `protobuf:"varint,6,opt,name=deck_copies,json=deckCopies,proto3" json:"deck_copies,omitempty"`
+	DeckJokers        int32 `protobuf:"varint,7,opt,name=deck_jokers,json=deckJokers,proto3" json:"deck_jokers,omitempty"`
+	FirstRound        int32 `protobuf:"varint,8,opt,name=first_round,json=firstRound,proto3" json:"first_round,omitempty"`
+	LastRound         int32 `protobuf:"varint,9,opt,name=last_round,json=lastRound,proto3" json:"last_round,omitempty"`
+}
+
+func (x *RuleSet) Reset() {
+	*x = RuleSet{}
+	mi := &file_bot_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleSet) ProtoMessage() {}
+
+func (x *RuleSet) ProtoReflect() protoreflect.Message {
+	mi := &file_bot_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleSet.ProtoReflect.Descriptor instead.
+func (*RuleSet) Descriptor() ([]byte, []int) {
+	return file_bot_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RuleSet) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RuleSet) GetJokerValue() int32 {
+	if x != nil {
+		return x.JokerValue
+	}
+	return 0
+}
+
+func (x *RuleSet) GetWildValue() int32 {
+	if x != nil {
+		return x.WildValue
+	}
+	return 0
+}
+
+func (x *RuleSet) GetMinSequenceLength() int32 {
+	if x != nil {
+		return x.MinSequenceLength
+	}
+	return 0
+}
+
+func (x *RuleSet) GetWrapRuns() bool {
+	if x != nil {
+		return x.WrapRuns
+	}
+	return false
+}
+
+func (x *RuleSet) GetDeckCopies() int32 {
+	if x != nil {
+		return x.DeckCopies
+	}
+	return 0
+}
+
+func (x *RuleSet) GetDeckJokers() int32 {
+	if x != nil {
+		return x.DeckJokers
+	}
+	return 0
+}
+
+func (x *RuleSet) GetFirstRound() int32 {
+	if x != nil {
+		return x.FirstRound
+	}
+	return 0
+}
+
+func (x *RuleSet) GetLastRound() int32 {
+	if x != nil {
+		return x.LastRound
+	}
+	return 0
+}
+
+// mirrors bots.OpponentTurn
+type OpponentTurn struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// seat relative to the player receiving the request, 1 is the opponent to the left
+	Seat int32 `protobuf:"varint,1,opt,name=seat,proto3" json:"seat,omitempty"`
+	Turn int32 `protobuf:"varint,2,opt,name=turn,proto3" json:"turn,omitempty"`
+	// deck or discard
+	Stack string `protobuf:"bytes,3,opt,name=stack,proto3" json:"stack,omitempty"`
+	// only set when the card was picked up from the discard pile
+	Drawn   string `protobuf:"bytes,4,opt,name=drawn,proto3" json:"drawn,omitempty"`
+	Discard string `protobuf:"bytes,5,opt,name=discard,proto3" json:"discard,omitempty"`
+}
+
+func (x *OpponentTurn) Reset() {
+	*x = OpponentTurn{}
+	mi := &file_bot_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpponentTurn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpponentTurn) ProtoMessage() {}
+
+func (x *OpponentTurn) ProtoReflect() protoreflect.Message {
+	mi := &file_bot_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpponentTurn.ProtoReflect.Descriptor instead.
+func (*OpponentTurn) Descriptor() ([]byte, []int) {
+	return file_bot_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OpponentTurn) GetSeat() int32 {
+	if x != nil {
+		return x.Seat
+	}
+	return 0
+}
+
+func (x *OpponentTurn) GetTurn() int32 {
+	if x != nil {
+		return x.Turn
+	}
+	return 0
+}
+
+func (x *OpponentTurn) GetStack() string {
+	if x != nil {
+		return x.Stack
+	}
+	return ""
+}
+
+func (x *OpponentTurn) GetDrawn() string {
+	if x != nil {
+		return x.Drawn
+	}
+	return ""
+}
+
+func (x *OpponentTurn) GetDiscard() string {
+	if x != nil {
+		return x.Discard
+	}
+	return ""
+}
+
+type Sequence struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cards []string `protobuf:"bytes,1,rep,name=cards,proto3" json:"cards,omitempty"`
+}
+
+func (x *Sequence) Reset() {
+	*x = Sequence{}
+	mi := &file_bot_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sequence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sequence) ProtoMessage() {}
+
+func (x *Sequence) ProtoReflect() protoreflect.Message {
+	mi := &file_bot_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sequence.ProtoReflect.Descriptor instead.
+func (*Sequence) Descriptor() ([]byte, []int) {
+	return file_bot_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Sequence) GetCards() []string {
+	if x != nil {
+		return x.Cards
+	}
+	return nil
+}
+
+type DrawResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Action string `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	// deck or discard
+	Stack string `protobuf:"bytes,2,opt,name=stack,proto3" json:"stack,omitempty"`
+}
+
+func (x *DrawResponse) Reset() {
+	*x = DrawResponse{}
+	mi := &file_bot_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrawResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrawResponse) ProtoMessage() {}
+
+func (x *DrawResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bot_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrawResponse.ProtoReflect.Descriptor instead.
+func (*DrawResponse) Descriptor() ([]byte, []int) {
+	return file_bot_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DrawResponse) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *DrawResponse) GetStack() string {
+	if x != nil {
+		return x.Stack
+	}
+	return ""
+}
+
+type DiscardResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Action    string      `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Card      string      `protobuf:"bytes,2,opt,name=card,proto3" json:"card,omitempty"`
+	Flop      bool        `protobuf:"varint,3,opt,name=flop,proto3" json:"flop,omitempty"`
+	Sequences []*Sequence `protobuf:"bytes,4,rep,name=sequences,proto3" json:"sequences,omitempty"`
+}
+
+func (x *DiscardResponse) Reset() {
+	*x = DiscardResponse{}
+	mi := &file_bot_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscardResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscardResponse) ProtoMessage() {}
+
+func (x *DiscardResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bot_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscardResponse.ProtoReflect.Descriptor instead.
+func (*DiscardResponse) Descriptor() ([]byte, []int) {
+	return file_bot_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DiscardResponse) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *DiscardResponse) GetCard() string {
+	if x != nil {
+		return x.Card
+	}
+	return ""
+}
+
+func (x *DiscardResponse) GetFlop() bool {
+	if x != nil {
+		return x.Flop
+	}
+	return false
+}
+
+func (x *DiscardResponse) GetSequences() []*Sequence {
+	if x != nil {
+		return x.Sequences
+	}
+	return nil
+}
+
+type ScoreResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Action    string      `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Flop      bool        `protobuf:"varint,2,opt,name=flop,proto3" json:"flop,omitempty"`
+	Sequences []*Sequence `protobuf:"bytes,3,rep,name=sequences,proto3" json:"sequences,omitempty"`
+}
+
+func (x *ScoreResponse) Reset() {
+	*x = ScoreResponse{}
+	mi := &file_bot_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreResponse) ProtoMessage() {}
+
+func (x *ScoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bot_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreResponse.ProtoReflect.Descriptor instead.
+func (*ScoreResponse) Descriptor() ([]byte, []int) {
+	return file_bot_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ScoreResponse) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ScoreResponse) GetFlop() bool {
+	if x != nil {
+		return x.Flop
+	}
+	return false
+}
+
+func (x *ScoreResponse) GetSequences() []*Sequence {
+	if x != nil {
+		return x.Sequences
+	}
+	return nil
+}
+
+var File_bot_proto protoreflect.FileDescriptor
+
+var file_bot_proto_rawDesc = []byte{
+	0x0a, 0x09, 0x62, 0x6f, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0e, 0x66, 0x69, 0x76,
+	0x65, 0x63, 0x72, 0x6f, 0x77, 0x6e, 0x73, 0x2e, 0x62, 0x6f, 0x74, 0x22, 0xf5, 0x02, 0x0a, 0x0a,
+	0x42, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x6f,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x62, 0x6f, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x70, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x6e, 0x64,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x74, 0x75, 0x72, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x54, 0x75, 0x72, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61,
+	0x6e, 0x64, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x6e, 0x64, 0x12, 0x1f,
+	0x0a, 0x0b, 0x6e, 0x65, 0x77, 0x65, 0x73, 0x74, 0x5f, 0x63, 0x61, 0x72, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x65, 0x77, 0x65, 0x73, 0x74, 0x43, 0x61, 0x72, 0x64, 0x12,
+	0x18, 0x0a, 0x07, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x07, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x12, 0x36, 0x0a, 0x07, 0x68, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x66, 0x69, 0x76,
+	0x65, 0x63, 0x72, 0x6f, 0x77, 0x6e, 0x73, 0x2e, 0x62, 0x6f, 0x74, 0x2e, 0x4f, 0x70, 0x70, 0x6f,
+	0x6e, 0x65, 0x6e, 0x74, 0x54, 0x75, 0x72, 0x6e, 0x52, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72,
+	0x79, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x75, 0x72, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x04, 0x74, 0x75, 0x72, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x63, 0x6b, 0x5f, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x64, 0x65, 0x63, 0x6b, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2d, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x66, 0x69, 0x76, 0x65, 0x63, 0x72, 0x6f, 0x77, 0x6e, 0x73,
+	0x2e, 0x62, 0x6f, 0x74, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x52, 0x05, 0x72, 0x75,
+	0x6c, 0x65, 0x73, 0x22, 0xac, 0x02, 0x0a, 0x07, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6a, 0x6f, 0x6b, 0x65, 0x72, 0x5f, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6a, 0x6f, 0x6b, 0x65, 0x72, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x69, 0x6c, 0x64, 0x5f, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x77, 0x69, 0x6c, 0x64, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x12, 0x2e, 0x0a, 0x13, 0x6d, 0x69, 0x6e, 0x5f, 0x73, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x11, 0x6d, 0x69, 0x6e, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x4c, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x12, 0x1b, 0x0a, 0x09, 0x77, 0x72, 0x61, 0x70, 0x5f, 0x72, 0x75, 0x6e, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x77, 0x72, 0x61, 0x70, 0x52, 0x75, 0x6e, 0x73,
+	0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65, 0x63, 0x6b, 0x5f, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x73, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x64, 0x65, 0x63, 0x6b, 0x43, 0x6f, 0x70, 0x69, 0x65,
+	0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65, 0x63, 0x6b, 0x5f, 0x6a, 0x6f, 0x6b, 0x65, 0x72, 0x73,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x64, 0x65, 0x63, 0x6b, 0x4a, 0x6f, 0x6b, 0x65,
+	0x72, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x72, 0x6f, 0x75, 0x6e,
+	0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x52, 0x6f,
+	0x75, 0x6e, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x72, 0x6f, 0x75, 0x6e,
+	0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x6f, 0x75,
+	0x6e, 0x64, 0x22, 0x7c, 0x0a, 0x0c, 0x4f, 0x70, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x54, 0x75,
+	0x72, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x04, 0x73, 0x65, 0x61, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x75, 0x72, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x75, 0x72, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x63, 0x6b,
+	0x12, 0x14, 0x0a, 0x05, 0x64, 0x72, 0x61, 0x77, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x64, 0x72, 0x61, 0x77, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64,
+	0x22, 0x20, 0x0a, 0x08, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x63, 0x61, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x63, 0x61, 0x72,
+	0x64, 0x73, 0x22, 0x3c, 0x0a, 0x0c, 0x44, 0x72, 0x61, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x63, 0x6b,
+	0x22, 0x89, 0x01, 0x0a, 0x0f, 0x44, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04,
+	0x63, 0x61, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x61, 0x72, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x66, 0x6c, 0x6f, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04,
+	0x66, 0x6c, 0x6f, 0x70, 0x12, 0x36, 0x0a, 0x09, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x66, 0x69, 0x76, 0x65, 0x63, 0x72,
+	0x6f, 0x77, 0x6e, 0x73, 0x2e, 0x62, 0x6f, 0x74, 0x2e, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x65, 0x52, 0x09, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x22, 0x73, 0x0a, 0x0d,
+	0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x6c, 0x6f, 0x70, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x04, 0x66, 0x6c, 0x6f, 0x70, 0x12, 0x36, 0x0a, 0x09, 0x73, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x66,
+	0x69, 0x76, 0x65, 0x63, 0x72, 0x6f, 0x77, 0x6e, 0x73, 0x2e, 0x62, 0x6f, 0x74, 0x2e, 0x53, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x09, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65,
+	0x73, 0x32, 0xda, 0x01, 0x0a, 0x0a, 0x42, 0x6f, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x40, 0x0a, 0x04, 0x44, 0x72, 0x61, 0x77, 0x12, 0x1a, 0x2e, 0x66, 0x69, 0x76, 0x65, 0x63,
+	0x72, 0x6f, 0x77, 0x6e, 0x73, 0x2e, 0x62, 0x6f, 0x74, 0x2e, 0x42, 0x6f, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x66, 0x69, 0x76, 0x65, 0x63, 0x72, 0x6f, 0x77, 0x6e,
+	0x73, 0x2e, 0x62, 0x6f, 0x74, 0x2e, 0x44, 0x72, 0x61, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x46, 0x0a, 0x07, 0x44, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x12, 0x1a, 0x2e,
+	0x66, 0x69, 0x76, 0x65, 0x63, 0x72, 0x6f, 0x77, 0x6e, 0x73, 0x2e, 0x62, 0x6f, 0x74, 0x2e, 0x42,
+	0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x66, 0x69, 0x76, 0x65,
+	0x63, 0x72, 0x6f, 0x77, 0x6e, 0x73, 0x2e, 0x62, 0x6f, 0x74, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x61,
+	0x72, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x05, 0x53, 0x63,
+	0x6f, 0x72, 0x65, 0x12, 0x1a, 0x2e, 0x66, 0x69, 0x76, 0x65, 0x63, 0x72, 0x6f, 0x77, 0x6e, 0x73,
+	0x2e, 0x62, 0x6f, 0x74, 0x2e, 0x42, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1d, 0x2e, 0x66, 0x69, 0x76, 0x65, 0x63, 0x72, 0x6f, 0x77, 0x6e, 0x73, 0x2e, 0x62, 0x6f, 0x74,
+	0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x32,
+	0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x69, 0x6d,
+	0x74, 0x61, 0x74, 0x74, 0x2f, 0x66, 0x69, 0x76, 0x65, 0x63, 0x72, 0x6f, 0x77, 0x6e, 0x73, 0x2f,
+	0x62, 0x6f, 0x74, 0x73, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x62, 0x6f, 0x74, 0x2f, 0x62, 0x6f, 0x74,
+	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_bot_proto_rawDescOnce sync.Once
+	file_bot_proto_rawDescData = file_bot_proto_rawDesc
+)
+
+func file_bot_proto_rawDescGZIP() []byte {
+	file_bot_proto_rawDescOnce.Do(func() {
+		file_bot_proto_rawDescData = protoimpl.X.CompressGZIP(file_bot_proto_rawDescData)
+	})
+	return file_bot_proto_rawDescData
+}
+
+var file_bot_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_bot_proto_goTypes = []any{
+	(*BotRequest)(nil),      // 0: fivecrowns.bot.BotRequest
+	(*RuleSet)(nil),         // 1: fivecrowns.bot.RuleSet
+	(*OpponentTurn)(nil),    // 2: fivecrowns.bot.OpponentTurn
+	(*Sequence)(nil),        // 3: fivecrowns.bot.Sequence
+	(*DrawResponse)(nil),    // 4: fivecrowns.bot.DrawResponse
+	(*DiscardResponse)(nil), // 5: fivecrowns.bot.DiscardResponse
+	(*ScoreResponse)(nil),   // 6: fivecrowns.bot.ScoreResponse
+}
+var file_bot_proto_depIdxs = []int32{
+	2, // 0: fivecrowns.bot.BotRequest.history:type_name -> fivecrowns.bot.OpponentTurn
+	1, // 1: fivecrowns.bot.BotRequest.rules:type_name -> fivecrowns.bot.RuleSet
+	3, // 2: fivecrowns.bot.DiscardResponse.sequences:type_name -> fivecrowns.bot.Sequence
+	3, // 3: fivecrowns.bot.ScoreResponse.sequences:type_name -> fivecrowns.bot.Sequence
+	0, // 4: fivecrowns.bot.BotService.Draw:input_type -> fivecrowns.bot.BotRequest
+	0, // 5: fivecrowns.bot.BotService.Discard:input_type -> fivecrowns.bot.BotRequest
+	0, // 6: fivecrowns.bot.BotService.Score:input_type -> fivecrowns.bot.BotRequest
+	4, // 7: fivecrowns.bot.BotService.Draw:output_type -> fivecrowns.bot.DrawResponse
+	5, // 8: fivecrowns.bot.BotService.Discard:output_type -> fivecrowns.bot.DiscardResponse
+	6, // 9: fivecrowns.bot.BotService.Score:output_type -> fivecrowns.bot.ScoreResponse
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_bot_proto_init() }
+func file_bot_proto_init() {
+	if File_bot_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_bot_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_bot_proto_goTypes,
+		DependencyIndexes: file_bot_proto_depIdxs,
+		MessageInfos:      file_bot_proto_msgTypes,
+	}.Build()
+	File_bot_proto = out.File
+	file_bot_proto_rawDesc = nil
+	file_bot_proto_goTypes = nil
+	file_bot_proto_depIdxs = nil
+}
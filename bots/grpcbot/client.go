@@ -0,0 +1,85 @@
+package grpcbot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grpcbot/botpb"
+	"google.golang.org/grpc"
+)
+
+// GrpcBot plays through a remote bot which implements the BotService
+
+type grpcBot struct {
+	client  botpb.BotServiceClient
+	name    string
+	timeout time.Duration
+}
+
+// name is sent with every request to choose the bot on the server. Server looks it up by name, so it is always required
+func NewGRPCBot(conn grpc.ClientConnInterface, name string, timeout time.Duration) bots.Bot {
+	return &grpcBot{
+		client:  botpb.NewBotServiceClient(conn),
+		name:    name,
+		timeout: timeout,
+	}
+}
+
+func (b *grpcBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	req.Action = bots.ActionDraw
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	res, err := b.client.Draw(ctx, toProtoRequest(b.name, req))
+
+	if err != nil {
+		return bots.DrawResponse{}, fmt.Errorf("grpc draw request failed: %w", err)
+	}
+
+	return bots.DrawResponse{
+		Action: bots.Action(res.GetAction()),
+		Stack:  bots.Stack(res.GetStack()),
+	}, nil
+}
+
+func (b *grpcBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	req.Action = bots.ActionDiscard
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	res, err := b.client.Discard(ctx, toProtoRequest(b.name, req))
+
+	if err != nil {
+		return bots.DiscardResponse{}, fmt.Errorf("grpc discard request failed: %w", err)
+	}
+
+	return bots.DiscardResponse{
+		Action:    bots.Action(res.GetAction()),
+		Card:      res.GetCard(),
+		Flop:      res.GetFlop(),
+		Sequences: fromProtoSequences(res.GetSequences()),
+	}, nil
+}
+
+func (b *grpcBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	req.Action = bots.ActionScore
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	res, err := b.client.Score(ctx, toProtoRequest(b.name, req))
+
+	if err != nil {
+		return bots.ScoreResponse{}, fmt.Errorf("grpc score request failed: %w", err)
+	}
+
+	return bots.ScoreResponse{
+		Action:    bots.Action(res.GetAction()),
+		Flop:      res.GetFlop(),
+		Sequences: fromProtoSequences(res.GetSequences()),
+	}, nil
+}
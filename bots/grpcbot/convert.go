@@ -0,0 +1,174 @@
+package grpcbot
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grpcbot/botpb"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+// converts between the bots package types and their protobuf equivalents
+
+func toProtoRequest(botName string, req bots.BotRequest) *botpb.BotRequest {
+	return &botpb.BotRequest{
+		Bot:         botName,
+		Action:      string(req.Action),
+		PlayerCount: int32(req.PlayerCount),
+		Round:       int32(req.Round),
+		LastTurn:    req.LastTurn,
+		Turn:        int32(req.Turn),
+		DeckCount:   int32(req.DeckCount),
+		Hand:        game.EncodeCards(req.Hand),
+		NewestCard:  req.NewestCard.Encode(),
+		Discard:     game.EncodeCards(req.Discard),
+		History:     toProtoHistory(req.History),
+		Rules:       toProtoRules(req.Rules),
+	}
+}
+
+// cards arrive as codes, so requests with invalid cards are rejected
+func fromProtoRequest(req *botpb.BotRequest) (bots.BotRequest, error) {
+	hand, err := game.DecodeCards(req.GetHand())
+
+	if err != nil {
+		return bots.BotRequest{}, fmt.Errorf("unable to decode hand: %w", err)
+	}
+
+	discard, err := game.DecodeCards(req.GetDiscard())
+
+	if err != nil {
+		return bots.BotRequest{}, fmt.Errorf("unable to decode discard pile: %w", err)
+	}
+
+	newest, err := decodeCard(req.GetNewestCard())
+
+	if err != nil {
+		return bots.BotRequest{}, fmt.Errorf("unable to decode newest card: %w", err)
+	}
+
+	history, err := fromProtoHistory(req.GetHistory())
+
+	if err != nil {
+		return bots.BotRequest{}, fmt.Errorf("unable to decode history: %w", err)
+	}
+
+	return bots.BotRequest{
+		Action:      bots.Action(req.Action),
+		PlayerCount: int(req.PlayerCount),
+		Round:       int(req.Round),
+		LastTurn:    req.LastTurn,
+		Turn:        int(req.Turn),
+		DeckCount:   int(req.DeckCount),
+		Hand:        hand,
+		NewestCard:  newest,
+		Discard:     discard,
+		History:     history,
+		Rules:       fromProtoRules(req.Rules),
+	}, nil
+}
+
+// decodes a card which may be missing, the empty string is the nil card
+func decodeCard(c string) (game.Card, error) {
+	if c == "" {
+		return game.Card{}, nil
+	}
+
+	return game.DecodeCard(c)
+}
+
+func toProtoRules(rules *game.RuleSet) *botpb.RuleSet {
+	if rules == nil {
+		return nil
+	}
+
+	return &botpb.RuleSet{
+		Name:              rules.Name,
+		JokerValue:        int32(rules.JokerValue),
+		WildValue:         int32(rules.WildValue),
+		MinSequenceLength: int32(rules.MinSequenceLength),
+		WrapRuns:          rules.WrapRuns,
+		DeckCopies:        int32(rules.DeckCopies),
+		DeckJokers:        int32(rules.DeckJokers),
+		FirstRound:        int32(rules.FirstRound),
+		LastRound:         int32(rules.LastRound),
+	}
+}
+
+func fromProtoRules(rules *botpb.RuleSet) *game.RuleSet {
+	if rules == nil {
+		return nil
+	}
+
+	return &game.RuleSet{
+		Name:              rules.Name,
+		JokerValue:        int(rules.JokerValue),
+		WildValue:         int(rules.WildValue),
+		MinSequenceLength: int(rules.MinSequenceLength),
+		WrapRuns:          rules.WrapRuns,
+		DeckCopies:        int(rules.DeckCopies),
+		DeckJokers:        int(rules.DeckJokers),
+		FirstRound:        int(rules.FirstRound),
+		LastRound:         int(rules.LastRound),
+	}
+}
+
+func toProtoHistory(history []bots.OpponentTurn) []*botpb.OpponentTurn {
+	out := make([]*botpb.OpponentTurn, len(history))
+
+	for i, turn := range history {
+		out[i] = &botpb.OpponentTurn{
+			Seat:    int32(turn.Seat),
+			Turn:    int32(turn.Turn),
+			Stack:   string(turn.Stack),
+			Drawn:   turn.Drawn.Encode(),
+			Discard: turn.Discard.Encode(),
+		}
+	}
+
+	return out
+}
+
+func fromProtoHistory(history []*botpb.OpponentTurn) ([]bots.OpponentTurn, error) {
+	out := make([]bots.OpponentTurn, len(history))
+
+	var errs error
+	for i, turn := range history {
+		drawn, err := decodeCard(turn.GetDrawn())
+		errs = errors.Join(errs, err)
+
+		discard, err := decodeCard(turn.GetDiscard())
+		errs = errors.Join(errs, err)
+
+		out[i] = bots.OpponentTurn{
+			Seat:    int(turn.GetSeat()),
+			Turn:    int(turn.GetTurn()),
+			Stack:   bots.Stack(turn.GetStack()),
+			Drawn:   drawn,
+			Discard: discard,
+		}
+	}
+
+	return out, errs
+}
+
+func toProtoSequences(seqs [][]string) []*botpb.Sequence {
+	out := make([]*botpb.Sequence, len(seqs))
+
+	for i, seq := range seqs {
+		out[i] = &botpb.Sequence{Cards: seq}
+	}
+
+	return out
+}
+
+func fromProtoSequences(seqs []*botpb.Sequence) [][]string {
+	out := make([][]string, len(seqs))
+
+	for i, seq := range seqs {
+		out[i] = seq.GetCards()
+	}
+
+	return out
+}
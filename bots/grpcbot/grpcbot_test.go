@@ -0,0 +1,112 @@
+package grpcbot
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grpcbot/botpb"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/game"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func startServer(t *testing.T) *grpc.ClientConn {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	botpb.RegisterBotServiceServer(server, NewServer(map[string]bots.Bot{
+		"grugbot": grugbot.NewGrugBot(),
+	}))
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestGRPCBot(t *testing.T) {
+
+	bot := NewGRPCBot(startServer(t), "grugbot", time.Second)
+
+	draw, err := bot.Draw(bots.BotRequest{
+		Hand:    game.MustDecodeSequence("9-R:10-R:5-X:8-R:6-B:8-B:11-R:11-Y:4-Y"),
+		Round:   9,
+		Discard: game.MustDecodeSequence("11-R"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, bots.ActionDraw, draw.Action)
+	assert.Equal(t, bots.StackDiscard, draw.Stack)
+
+	discard, err := bot.Discard(bots.BotRequest{
+		Hand:  game.MustDecodeSequence("5-X:3-B:5-R:9-Y:13-B:11-X:6-Y"),
+		Round: 7,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "13-B", discard.Card)
+
+	score, err := bot.Score(bots.BotRequest{
+		Hand:  game.MustDecodeSequence("5-B:*:5-R:4-B:6-B"),
+		Round: 10,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4-B:5-B:6-B:*", "5-R"}, game.FlattenSequences(score.Sequences))
+}
+
+func TestGRPCBotUnknownBot(t *testing.T) {
+
+	bot := NewGRPCBot(startServer(t), "galaxybrainbot", time.Second)
+
+	_, err := bot.Score(bots.BotRequest{
+		Hand:  game.MustDecodeSequence("5-B"),
+		Round: 10,
+	})
+
+	assert.ErrorContains(t, err, "unknown bot")
+}
+
+func TestConvertRequest(t *testing.T) {
+
+	req := bots.BotRequest{
+		Action:      bots.ActionDiscard,
+		PlayerCount: 3,
+		Round:       5,
+		Turn:        3,
+		DeckCount:   80,
+		Hand:        game.MustDecodeSequence("5-B:*"),
+		NewestCard:  game.CardJoker,
+		Discard:     game.MustDecodeSequence("9-R:4-Y"),
+		History: []bots.OpponentTurn{
+			{Seat: 1, Turn: 1, Stack: bots.StackDiscard, Drawn: game.MustDecodeCard("3-R"), Discard: game.MustDecodeCard("4-Y")},
+			{Seat: 2, Turn: 2, Stack: bots.StackDeck, Discard: game.MustDecodeCard("9-R")},
+		},
+	}
+
+	converted, err := fromProtoRequest(toProtoRequest("grugbot", req))
+	require.NoError(t, err)
+	assert.Equal(t, req, converted)
+
+	req.Rules = &game.OfficialRules
+	converted, err = fromProtoRequest(toProtoRequest("grugbot", req))
+	require.NoError(t, err)
+	assert.Equal(t, req, converted)
+
+	// cards arrive as codes, so invalid ones are rejected
+	invalid := toProtoRequest("grugbot", req)
+	invalid.Hand = append(invalid.Hand, "99-Z")
+
+	_, err = fromProtoRequest(invalid)
+	assert.Error(t, err)
+}
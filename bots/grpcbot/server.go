@@ -0,0 +1,116 @@
+package grpcbot
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grpcbot/botpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server exposes bots over gRPC
+// the bot is chosen by the name in each request
+
+type Server struct {
+	botpb.UnimplementedBotServiceServer
+
+	lookup func(name string) (bots.Bot, bool)
+}
+
+func NewServer(b map[string]bots.Bot) *Server {
+	return NewLookupServer(func(name string) (bots.Bot, bool) {
+		bot, ok := b[name]
+		return bot, ok
+	})
+}
+
+// serves whichever bot the lookup returns, so bots can be added and removed while the server runs
+func NewLookupServer(lookup func(name string) (bots.Bot, bool)) *Server {
+	return &Server{
+		lookup: lookup,
+	}
+}
+
+func (s *Server) Draw(ctx context.Context, req *botpb.BotRequest) (*botpb.DrawResponse, error) {
+	bot, botReq, err := s.bot(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := bot.Draw(botReq)
+
+	if err != nil {
+		slog.Error("failed to get bot response", "bot", req.GetBot(), "err", err)
+		return nil, status.Errorf(codes.Internal, "failed to draw: %s", err)
+	}
+
+	return &botpb.DrawResponse{
+		Action: string(res.Action),
+		Stack:  string(res.Stack),
+	}, nil
+}
+
+func (s *Server) Discard(ctx context.Context, req *botpb.BotRequest) (*botpb.DiscardResponse, error) {
+	bot, botReq, err := s.bot(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := bot.Discard(botReq)
+
+	if err != nil {
+		slog.Error("failed to get bot response", "bot", req.GetBot(), "err", err)
+		return nil, status.Errorf(codes.Internal, "failed to discard: %s", err)
+	}
+
+	return &botpb.DiscardResponse{
+		Action:    string(res.Action),
+		Card:      res.Card,
+		Flop:      res.Flop,
+		Sequences: toProtoSequences(res.Sequences),
+	}, nil
+}
+
+func (s *Server) Score(ctx context.Context, req *botpb.BotRequest) (*botpb.ScoreResponse, error) {
+	bot, botReq, err := s.bot(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := bot.Score(botReq)
+
+	if err != nil {
+		slog.Error("failed to get bot response", "bot", req.GetBot(), "err", err)
+		return nil, status.Errorf(codes.Internal, "failed to score: %s", err)
+	}
+
+	return &botpb.ScoreResponse{
+		Action:    string(res.Action),
+		Flop:      res.Flop,
+		Sequences: toProtoSequences(res.Sequences),
+	}, nil
+}
+
+// finds the bot the request is for and decodes the request for it
+func (s *Server) bot(req *botpb.BotRequest) (bots.Bot, bots.BotRequest, error) {
+	slog.Info("received request", "action", req.GetAction(), "bot", req.GetBot())
+
+	bot, ok := s.lookup(req.GetBot())
+
+	if !ok {
+		return nil, bots.BotRequest{}, status.Errorf(codes.NotFound, "unknown bot: %s", req.GetBot())
+	}
+
+	botReq, err := fromProtoRequest(req)
+
+	if err != nil {
+		return nil, bots.BotRequest{}, status.Errorf(codes.InvalidArgument, "invalid request: %s", err)
+	}
+
+	return bot, botReq, nil
+}
@@ -2,11 +2,11 @@ package grugbot
 
 import (
 	"errors"
-	"fmt"
 	"log/slog"
 	"slices"
 
 	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/strategy"
 	"github.com/timtatt/fivecrowns/game"
 	"github.com/timtatt/fivecrowns/math"
 )
@@ -28,45 +28,42 @@ type Calculation struct {
 
 func (b *grugBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
 
-	calculation, err := Calculate(req)
-
-	if err != nil {
-		return bots.ScoreResponse{}, fmt.Errorf("cannot calculate response: %w", err)
-	}
+	calculation := Calculate(req)
 
 	return bots.ScoreResponse{
 		Action:    req.Action,
-		Flop:      game.CanFlop(calculation.Sequences),
+		Flop:      req.RuleSet().CanFlop(calculation.Sequences, req.Round),
 		Sequences: game.EncodeSequences(calculation.Sequences),
 	}, nil
 }
 
 func (b *grugBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
 
-	// add the discard to the hand and determine if it gets added to a valid sequence
+	// nothing to pick up at the start of a round
+	if len(req.Discard) == 0 {
+		return bots.DrawResponse{
+			Action: req.Action,
+			Stack:  bots.StackDeck,
+		}, nil
+	}
 
-	topCard, err := game.DecodeCard(req.Discard[0])
+	// add the discard to the hand and determine if it gets added to a valid sequence
 
-	if err != nil {
-		return bots.DrawResponse{}, fmt.Errorf("unable to decode discard card: %w", err)
-	}
+	topCard := req.Discard[0]
 
-	hypothetical, err := Calculate(bots.BotRequest{
+	hypothetical := Calculate(bots.BotRequest{
 		Action:  req.Action,
-		Hand:    append(req.Hand, req.Discard[0]),
+		Hand:    append(slices.Clone(req.Hand), topCard),
 		Round:   req.Round,
 		Discard: req.Discard,
+		Rules:   req.Rules,
 	})
 
-	if err != nil {
-		return bots.DrawResponse{}, fmt.Errorf("unable to hypothesise discard score: %w", err)
-	}
-
 	// determine if the topCard has been used in a sequence
 	// goes in reverse and checks if there is an invalid sequence with only the topCard
 	for i := len(hypothetical.Sequences) - 1; i >= 0; i-- {
 		// available optimisation: if sequences are now valid, the top card will be there somewhere. we don't need to know where
-		if len(hypothetical.Sequences[i]) >= 3 {
+		if len(hypothetical.Sequences[i]) >= req.RuleSet().MinSequenceLength {
 			return bots.DrawResponse{
 				Action: req.Action,
 				Stack:  bots.StackDiscard,
@@ -87,14 +84,17 @@ func (b *grugBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
 
 func (b *grugBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
 
-	calculation, err := Calculate(req)
-
-	if err != nil {
-		return bots.DiscardResponse{}, fmt.Errorf("unable to calculate score: %w", err)
-	}
+	calculation := Calculate(req)
 
 	// determine which card is the highest one that is not in a valid sequence
-	worstCard := WorstCard(req.Round, calculation.Sequences, req.LastTurn)
+	worstCard := WorstCard(req.RuleSet(), req.Round, calculation.Sequences, req.LastTurn)
+
+	// when an opponent is likely to go out soon, shed the most expensive card rather than building sequences
+	if strategy.DamageControl(req) {
+		if shed, ok := strategy.Shed(req.RuleSet(), req.Round, calculation.Sequences, strategy.FlopChance(req)); ok {
+			worstCard = CardAndLocation(shed)
+		}
+	}
 
 	slog.Info("worst card detected", "card", worstCard)
 
@@ -110,7 +110,7 @@ func (b *grugBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
 	}
 
 	return bots.DiscardResponse{
-		Flop:      game.CanFlop(calculation.Sequences),
+		Flop:      req.RuleSet().CanFlop(calculation.Sequences, req.Round),
 		Sequences: game.EncodeSequences(calculation.Sequences),
 		Action:    bots.ActionDiscard,
 		Card:      worstCard.Card.Encode(),
@@ -118,12 +118,8 @@ func (b *grugBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
 }
 
 // calculate best possible sequences
-func Calculate(req bots.BotRequest) (Calculation, error) {
-	hand, err := game.DecodeCards(req.Hand)
-
-	if err != nil {
-		return Calculation{}, fmt.Errorf("unable to decode cards: %w", err)
-	}
+func Calculate(req bots.BotRequest) Calculation {
+	hand := slices.Clone(req.Hand)
 
 	// sort the cards by suite and number
 	slices.SortFunc(hand, game.CompareCard)
@@ -131,16 +127,16 @@ func Calculate(req bots.BotRequest) (Calculation, error) {
 	// find all possible sequences in the hand
 	seqs := FindSequences(req.Round, hand)
 
-	slog.Info("calculated possible sequences", "seqs", game.EncodeSequences(seqs))
+	slog.Debug("calculated possible sequences", "seqs", game.EncodeSequences(seqs))
 
 	// filter out sequences if they have cards that have been used twiced
 	// use the wilds to build more sequences
-	seqs = FilterSequences(req.Round, hand, seqs)
+	seqs = FilterSequences(req.RuleSet(), req.Round, hand, seqs)
 
 	return Calculation{
-		Flop:      game.CanFlop(seqs),
+		Flop:      req.RuleSet().CanFlop(seqs, req.Round),
 		Sequences: seqs,
-	}, nil
+	}
 }
 
 // takes a list of cards and returns a map with the counts of each card
@@ -160,7 +156,7 @@ type CardAndLocation struct {
 	CardIdx     int
 }
 
-func WorstCard(round int, seqs [][]game.Card, lastTurn bool) CardAndLocation {
+func WorstCard(rules game.RuleSet, round int, seqs [][]game.Card, lastTurn bool) CardAndLocation {
 
 	// save the card and its location to easily remove it in the future
 	var worstCard CardAndLocation
@@ -169,9 +165,9 @@ func WorstCard(round int, seqs [][]game.Card, lastTurn bool) CardAndLocation {
 		seq := seqs[i]
 
 		// if it is the last turn, we just want to throw out our highest card, even if it is in a partial sequence
-		threshold := 2
+		threshold := rules.MinSequenceLength - 1
 		if lastTurn {
-			threshold = 3
+			threshold = rules.MinSequenceLength
 		}
 
 		// skip seq if above keeping threshold
@@ -181,7 +177,7 @@ func WorstCard(round int, seqs [][]game.Card, lastTurn bool) CardAndLocation {
 
 		// get the highest card in the current sequence
 		for j, card := range seq {
-			if game.ScoreCard(card) > game.ScoreCard(worstCard.Card) {
+			if rules.ScoreCard(card, round) > rules.ScoreCard(worstCard.Card, round) {
 				worstCard = CardAndLocation{
 					Card:        card,
 					SequenceIdx: i,
@@ -197,7 +193,7 @@ func WorstCard(round int, seqs [][]game.Card, lastTurn bool) CardAndLocation {
 
 	// if the hand can flop without discarding, a random card will need to be chosen to be omitted
 	for i, seq := range seqs {
-		if len(seq) <= 3 {
+		if len(seq) <= rules.MinSequenceLength {
 			// don't discard from a complete sequence which will break the sequence
 			continue
 		}
@@ -252,18 +248,29 @@ func FindSequences(round int, hand []game.Card) [][]game.Card {
 
 	// go through the sorted cards and find runs of numbers in the same suite
 
-	curSeq := []game.Card{hand[0]}
+	// wilds never start a run, otherwise they would be used as both a natural card and a wild
+	curSeq := make([]game.Card, 0, len(hand))
 
-	for i := 1; i < len(hand); i++ {
+	for i := 0; i < len(hand); i++ {
 
 		// check if the current number is in sequence with previous
 
 		c := hand[i]
-		pc := curSeq[len(curSeq)-1]
 
 		// if we are dealing with jokers, we can ignore them
+		if c.IsWild(round) {
+			continue
+		}
+
+		if len(curSeq) == 0 {
+			curSeq = append(curSeq, c)
+			continue
+		}
+
+		pc := curSeq[len(curSeq)-1]
+
 		// if the current card and previous card in sequence is the same, we can ignore it
-		if c.IsWild(round) || c == pc {
+		if c == pc {
 			continue
 		}
 
@@ -281,7 +288,7 @@ func FindSequences(round int, hand []game.Card) [][]game.Card {
 
 			// save the sequence if it qualifies
 			if len(curSeq) >= 2 {
-				slog.Info("adding sequence", "seq", game.EncodeSequence(curSeq))
+				slog.Debug("adding sequence", "seq", game.EncodeSequence(curSeq))
 				seqs = append(seqs, curSeq)
 			}
 
@@ -293,11 +300,11 @@ func FindSequences(round int, hand []game.Card) [][]game.Card {
 	// ensure we don't leave a curSeq hanging
 	if len(curSeq) >= 2 {
 		seqs = append(seqs, curSeq)
-		slog.Info("adding sequence", "seq", game.EncodeSequence(curSeq))
+		slog.Debug("adding sequence", "seq", game.EncodeSequence(curSeq))
 	}
 
 	cardCounts := CardCounts(hand)
-	slog.Info("card counts in hand", "hand", cardCounts)
+	slog.Debug("card counts in hand", "hand", cardCounts)
 
 	// check the cards for sets
 	for number := 3; number <= 13; number++ {
@@ -309,7 +316,7 @@ func FindSequences(round int, hand []game.Card) [][]game.Card {
 
 		curSeq := make([]game.Card, 0)
 
-		for _, suite := range game.Suites {
+		for _, suite := range game.Suites() {
 
 			c := game.Card{
 				Joker:  false,
@@ -326,7 +333,7 @@ func FindSequences(round int, hand []game.Card) [][]game.Card {
 		if len(curSeq) >= 2 {
 			// add all single cards as a sequence too
 			seqs = append(seqs, curSeq)
-			slog.Info("adding sequence", "seq", game.EncodeSequence(curSeq))
+			slog.Debug("adding sequence", "seq", game.EncodeSequence(curSeq))
 		}
 	}
 
@@ -342,20 +349,20 @@ func FindSequences(round int, hand []game.Card) [][]game.Card {
 	return seqs
 }
 
-func CompareSequence(round int) func(a, b []game.Card) int {
+func CompareSequence(rules game.RuleSet, round int) func(a, b []game.Card) int {
 	return func(a, b []game.Card) int {
 		// sorts in reverse to optimise the sequence processing
 
 		// 1 = prefer a
 		// -1 = prefer b
 
-		if len(a) < 3 && len(b) >= 3 {
+		if len(a) < rules.MinSequenceLength && len(b) >= rules.MinSequenceLength {
 			return -1
-		} else if len(a) >= 3 && len(b) < 3 {
+		} else if len(a) >= rules.MinSequenceLength && len(b) < rules.MinSequenceLength {
 			return 1
 		}
 
-		scoreDiff := game.ScoreSequence(a) - game.ScoreSequence(b)
+		scoreDiff := rules.ScoreSequence(a, round) - rules.ScoreSequence(b, round)
 
 		if scoreDiff != 0 {
 			return scoreDiff
@@ -382,13 +389,13 @@ func CompareSequence(round int) func(a, b []game.Card) int {
 // determines if any card is being used more than it should be
 // if so, will chose the sequence with the highest score
 // output will include any cards which dont fit within a sequence as a single-carded sequence
-func FilterSequences(round int, hand []game.Card, seqs [][]game.Card) [][]game.Card {
+func FilterSequences(rules game.RuleSet, round int, hand []game.Card, seqs [][]game.Card) [][]game.Card {
 
 	// score all of the sequences
 
-	slices.SortFunc(seqs, CompareSequence(round))
+	slices.SortFunc(seqs, CompareSequence(rules, round))
 
-	slog.Info("sorting by sequence scores", "seqs", game.EncodeSequences(seqs))
+	slog.Debug("sorting by sequence scores", "seqs", game.EncodeSequences(seqs))
 
 	cardCounts := CardCounts(hand)
 
@@ -409,8 +416,9 @@ func FilterSequences(round int, hand []game.Card, seqs [][]game.Card) [][]game.C
 		z += 1
 
 		if z == 100 {
-			slog.Info("infinite loop", "filteredSeqs", game.EncodeSequences(filteredSeqs), "remainingSeqs", game.EncodeSequences(remainingSeqs))
-			panic("infinite loop detected")
+			// fall back to the exact arrangement rather than looping forever
+			slog.Warn("unable to filter sequences", "filteredSeqs", game.EncodeSequences(filteredSeqs), "remainingSeqs", game.EncodeSequences(remainingSeqs))
+			return rules.OptimalArrangement(hand, round).Hand()
 		}
 
 		// available optimisation: don't bother checking for card usage for first sequence
@@ -430,7 +438,7 @@ func FilterSequences(round int, hand []game.Card, seqs [][]game.Card) [][]game.C
 
 				continue
 			} else if len(availableCards) != len(seq) {
-				slog.Info("missing cards to build sequence", "needs", game.EncodeCards(seq), "available", game.EncodeCards(availableCards))
+				slog.Debug("missing cards to build sequence", "needs", game.EncodeCards(seq), "available", game.EncodeCards(availableCards))
 				// there are some missing cards, this seq cannot be used as is
 
 				// add this remaining seq back into the remainingSeqs
@@ -438,14 +446,14 @@ func FilterSequences(round int, hand []game.Card, seqs [][]game.Card) [][]game.C
 				remainingSeqs[lastIdx] = availableCards
 
 				// resort the remainingSeqs
-				slices.SortFunc(remainingSeqs, CompareSequence(round))
+				slices.SortFunc(remainingSeqs, CompareSequence(rules, round))
 
 				continue
 			}
 		}
 
-		// add jokers to the sequence if the sequence is < 3 cards in length
-		gap := 3 - len(seq)
+		// add jokers to the sequence if the sequence is too short to play
+		gap := rules.MinSequenceLength - len(seq)
 		if gap > 0 && wildCount(cardCounts, round) >= gap {
 			for range gap {
 
@@ -454,7 +462,7 @@ func FilterSequences(round int, hand []game.Card, seqs [][]game.Card) [][]game.C
 
 				// ignoring the err, we know there will be a wild available here
 				wc, err := getWild(cardCounts, round)
-				slog.Info("add a wild to seq", "seq", game.EncodeSequence(seq), "wild", wc)
+				slog.Debug("add a wild to seq", "seq", game.EncodeSequence(seq), "wild", wc)
 
 				if err != nil {
 					// this should not occur
@@ -480,7 +488,7 @@ func FilterSequences(round int, hand []game.Card, seqs [][]game.Card) [][]game.C
 		remainingSeqs = slices.Delete(remainingSeqs, lastIdx, lastIdx+1)
 	}
 
-	slog.Info("remaining card counts", "counts", cardCounts)
+	slog.Debug("remaining card counts", "counts", cardCounts)
 
 	for {
 		wc, err := getWild(cardCounts, round)
@@ -509,7 +517,7 @@ func getWild(cardCounts map[game.Card]int, round int) (game.Card, error) {
 		return game.CardJoker, nil
 	}
 
-	for _, s := range game.Suites {
+	for _, s := range game.Suites() {
 		c := game.Card{
 			Number: round,
 			Suite:  s,
@@ -529,7 +537,7 @@ func wildCount(cardCounts map[game.Card]int, round int) int {
 
 	count += cardCounts[game.CardJoker]
 
-	for _, s := range game.Suites {
+	for _, s := range game.Suites() {
 		c := game.Card{
 			Number: round,
 			Suite:  s,
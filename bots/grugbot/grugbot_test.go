@@ -1,7 +1,8 @@
 package grugbot
 
 import (
-	"strings"
+	"math/rand/v2"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,11 +11,21 @@ import (
 	"github.com/timtatt/fivecrowns/game"
 )
 
+// sequences need at least 4 cards
+var longRules = func() game.RuleSet {
+	long := game.OfficialRules
+	long.Name = "long"
+	long.MinSequenceLength = 4
+
+	return long
+}()
+
 func TestGrugbotScore(t *testing.T) {
 
 	cases := []struct {
 		Hand     string
 		Round    int
+		Rules    *game.RuleSet
 		Expected []string
 	}{
 		{
@@ -83,6 +94,44 @@ func TestGrugbotScore(t *testing.T) {
 				"6-Y",
 			},
 		},
+		{
+			// the wild 5-B sorts first, but is only used once
+			Hand:  "5-B:6-B:7-B:9-R",
+			Round: 5,
+			Expected: []string{
+				"6-B:7-B:5-B",
+				"9-R",
+			},
+		},
+		{
+			Hand:  "5-B:*:5-R:4-B:6-B",
+			Round: 10,
+			Rules: &game.OfficialRules,
+			Expected: []string{
+				"4-B:5-B:6-B:*",
+				"5-R",
+			},
+		},
+		{
+			Hand:  "9-R:9-Y:9-B:*:4-B:5-B:12-X",
+			Round: 10,
+			Rules: &game.OfficialRules,
+			Expected: []string{
+				"9-B:9-R:9-Y",
+				"12-X",
+				"4-B:5-B:*",
+			},
+		},
+		{
+			// each sequence needs a wild to reach 4 cards
+			Hand:  "7-R:8-R:9-R:11-B:11-G:11-Y:*:*",
+			Round: 10,
+			Rules: &longRules,
+			Expected: []string{
+				"11-B:11-G:11-Y:*",
+				"7-R:8-R:9-R:*",
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -90,17 +139,30 @@ func TestGrugbotScore(t *testing.T) {
 		gb := NewGrugBot()
 
 		t.Run("test best sequence from hand: "+tc.Hand, func(t *testing.T) {
-			hand := strings.Split(tc.Hand, ":")
+			hand := game.MustDecodeSequence(tc.Hand)
 
-			res, err := gb.Score(bots.BotRequest{
+			req := bots.BotRequest{
 				Action: bots.ActionScore,
 				Hand:   hand,
 				Round:  tc.Round,
-			})
+				Rules:  tc.Rules,
+			}
+
+			res, err := gb.Score(req)
 
 			assert.NoError(t, err)
 			assert.Equal(t, tc.Expected, game.FlattenSequences(res.Sequences))
 
+			// every complete sequence must be a legal run or set
+			for _, seq := range res.Sequences {
+				if len(seq) >= req.RuleSet().MinSequenceLength {
+					cards, err := game.DecodeCards(seq)
+
+					require.NoError(t, err)
+					assert.NoError(t, req.RuleSet().ValidateSequence(cards, tc.Round))
+				}
+			}
+
 		})
 
 	}
@@ -113,6 +175,7 @@ func TestGrugbotDraw(t *testing.T) {
 		Hand     string
 		Round    int
 		Discard  string
+		Rules    *game.RuleSet
 		Expected bots.Stack
 	}{
 		{
@@ -127,6 +190,28 @@ func TestGrugbotDraw(t *testing.T) {
 			Discard:  "4-G",
 			Expected: bots.StackDeck,
 		},
+		{
+			// the discard pile is empty at the start of a round
+			Hand:     "9-R:10-R:5-X:8-R:6-B:8-B:11-R:11-Y:4-Y",
+			Round:    9,
+			Discard:  "",
+			Expected: bots.StackDeck,
+		},
+		{
+			Hand:     "9-R:10-R:5-X:8-R:6-B:8-B:11-R:11-Y:4-Y",
+			Round:    9,
+			Discard:  "11-R",
+			Rules:    &game.OfficialRules,
+			Expected: bots.StackDiscard,
+		},
+		{
+			// a run of 3 is not enough to go out
+			Hand:     "9-R:10-R:5-X:4-Y",
+			Round:    6,
+			Discard:  "11-R",
+			Rules:    &longRules,
+			Expected: bots.StackDeck,
+		},
 	}
 
 	for _, tc := range cases {
@@ -134,13 +219,16 @@ func TestGrugbotDraw(t *testing.T) {
 		gb := NewGrugBot()
 
 		t.Run("test best stack to draw from hand: "+tc.Hand, func(t *testing.T) {
-			hand := strings.Split(tc.Hand, ":")
+			hand := game.MustDecodeSequence(tc.Hand)
+
+			discard := game.MustDecodeSequence(tc.Discard)
 
 			res, err := gb.Draw(bots.BotRequest{
 				Action:  bots.ActionScore,
 				Hand:    hand,
 				Round:   tc.Round,
-				Discard: []string{tc.Discard},
+				Discard: discard,
+				Rules:   tc.Rules,
 			})
 
 			assert.NoError(t, err)
@@ -181,7 +269,7 @@ func TestGrugbotDiscard(t *testing.T) {
 		gb := NewGrugBot()
 
 		t.Run("test best stack to draw from hand: "+tc.Hand, func(t *testing.T) {
-			hand := strings.Split(tc.Hand, ":")
+			hand := game.MustDecodeSequence(tc.Hand)
 
 			res, err := gb.Discard(bots.BotRequest{
 				Action: bots.ActionScore,
@@ -260,10 +348,45 @@ func TestGrugbotWorstCard(t *testing.T) {
 
 			require.NoError(t, err)
 
-			res := WorstCard(tc.Round, seqs, tc.LastTurn)
+			res := WorstCard(game.HouseRules, tc.Round, seqs, tc.LastTurn)
 
 			assert.Equal(t, tc.Expected, res.Card.Encode())
 		})
 	}
 
 }
+
+// measures the greedy sequencing against the exact arrangement
+func TestGrugbotAgainstOptimal(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+
+	optimal := 0
+	hands := 500
+
+	for range hands {
+		deck := game.NewDeck()
+		deck.Shuffle(r)
+
+		round := 3 + r.IntN(11)
+		hand := slices.Clone(deck.Cards()[:round+1])
+
+		calculation := Calculate(bots.BotRequest{
+			Hand:  hand,
+			Round: round,
+		})
+
+		// every card is used exactly once
+		require.ElementsMatch(t, hand, slices.Concat(calculation.Sequences...))
+
+		greedy := game.ScorePenalty(calculation.Sequences, round)
+		best := game.OptimalArrangement(hand, round).Penalty
+
+		require.GreaterOrEqual(t, greedy, best, game.EncodeSequence(hand))
+
+		if greedy == best {
+			optimal += 1
+		}
+	}
+
+	t.Logf("greedy sequencing was optimal for %d of %d hands", optimal, hands)
+}
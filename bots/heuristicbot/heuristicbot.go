@@ -0,0 +1,211 @@
+package heuristicbot
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"slices"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/strategy"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+// Heuristicbot values a hand with a weighted sum of its penalty, partial sequences and wilds
+// it draws and discards to keep the hand with the lowest value. the weights are loaded from a file and can be tuned
+
+type heuristicBot struct {
+	weights Weights
+}
+
+func NewHeuristicBot(weights Weights) bots.Bot {
+	return &heuristicBot{
+		weights: weights,
+	}
+}
+
+func (b *heuristicBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	rules := req.RuleSet()
+	seqs, _ := b.evaluate(rules, req.Round, req.Hand, 1)
+
+	return bots.ScoreResponse{
+		Action:    req.Action,
+		Flop:      rules.CanFlop(seqs, req.Round),
+		Sequences: game.EncodeSequences(seqs),
+	}, nil
+}
+
+func (b *heuristicBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	hand := req.Hand
+	stack := bots.StackDeck
+
+	if len(req.Discard) > 0 && !req.Discard[0].IsNil() {
+		top := req.Discard[0]
+		rules := req.RuleSet()
+		partial := b.partialWeight(req)
+
+		_, _, fromDiscard := b.best(rules, req.Round, append(slices.Clone(hand), top), partial)
+		_, current := b.evaluate(rules, req.Round, hand, partial)
+
+		slog.Info("hand values", "discard", fromDiscard, "current", current)
+
+		if fromDiscard+b.weights.DrawThreshold < current {
+			stack = bots.StackDiscard
+		}
+	}
+
+	return bots.DrawResponse{
+		Action: req.Action,
+		Stack:  stack,
+	}, nil
+}
+
+func (b *heuristicBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	if len(req.Hand) == 0 {
+		return bots.DiscardResponse{}, errors.New("cannot discard from an empty hand")
+	}
+
+	rules := req.RuleSet()
+	card, seqs, value := b.best(rules, req.Round, req.Hand, b.partialWeight(req))
+
+	slog.Info("discarding card", "card", card.Encode(), "value", value)
+
+	return bots.DiscardResponse{
+		Flop:      rules.CanFlop(seqs, req.Round),
+		Sequences: game.EncodeSequences(seqs),
+		Action:    bots.ActionDiscard,
+		Card:      card.Encode(),
+	}, nil
+}
+
+// how much of the credit for partial sequences still counts this turn
+func (b *heuristicBot) partialWeight(req bots.BotRequest) float64 {
+	if req.LastTurn || strategy.FlopChance(req) >= b.weights.DamageControl {
+		return 1 - b.weights.LastTurnAggression
+	}
+
+	return 1
+}
+
+// returns the discard which leaves the hand with the lowest value. ties are broken by discarding the highest card
+// a hand which can go out is always kept
+func (b *heuristicBot) best(rules game.RuleSet, round int, hand []game.Card, partial float64) (game.Card, [][]game.Card, float64) {
+	var best game.Card
+	var bestSeqs [][]game.Card
+	bestValue := math.Inf(1)
+
+	for i, card := range hand {
+		if slices.Index(hand, card) != i {
+			continue
+		}
+
+		seqs, value := b.evaluate(rules, round, slices.Delete(slices.Clone(hand), i, i+1), partial)
+
+		if rules.ScorePenalty(seqs, round) == 0 {
+			value = math.Inf(-1)
+		}
+
+		if value < bestValue || (value == bestValue && rules.ScoreCard(card, round) > rules.ScoreCard(best, round)) {
+			best = card
+			bestSeqs = seqs
+			bestValue = value
+		}
+	}
+
+	return best, bestSeqs, bestValue
+}
+
+// arranges the hand and returns its value, lower is better
+// of the arrangements with the lowest penalty, the one with the lowest value is kept
+func (b *heuristicBot) evaluate(rules game.RuleSet, round int, hand []game.Card, partial float64) ([][]game.Card, float64) {
+	var bestSeqs [][]game.Card
+	bestValue := math.Inf(1)
+
+	wilds := 0
+	for _, card := range hand {
+		if card.IsWild(round) {
+			wilds += 1
+		}
+	}
+
+	for _, arrangement := range rules.OptimalArrangements(hand, round) {
+		value := -b.weights.WildHoarding * float64(wilds)
+
+		for _, card := range arrangement.Leftover {
+			score := rules.ScoreCard(card, round)
+			value += float64(score) + b.weights.HighCardShedding*float64(max(score-7, 0))
+		}
+
+		pairs, singles := partials(rules, round, arrangement.Leftover)
+
+		for _, pair := range pairs {
+			bias := 1 - b.weights.SetBias
+			if pair[0].Number == pair[1].Number {
+				bias = 1 + b.weights.SetBias
+			}
+
+			value -= partial * b.weights.Partial * bias * float64(rules.ScoreSequence(pair, round))
+		}
+
+		// ties between arrangements go to the one with more sets, or more runs when the bias is negative
+		for _, seq := range arrangement.Sequences {
+			if game.GetSequenceType(seq, round) == game.SequenceTypeSet {
+				value -= b.weights.SetBias * 1e-3
+			}
+		}
+
+		if value < bestValue {
+			bestValue = value
+			bestSeqs = slices.Concat(arrangement.Sequences, pairs)
+
+			for _, card := range singles {
+				bestSeqs = append(bestSeqs, []game.Card{card})
+			}
+		}
+	}
+
+	return bestSeqs, bestValue
+}
+
+// pairs up leftover cards which are one card away from a set or a run, highest cards first
+// returns the pairs and the cards which could not be paired
+func partials(rules game.RuleSet, round int, leftover []game.Card) ([][]game.Card, []game.Card) {
+	cards := slices.Clone(leftover)
+	slices.SortFunc(cards, func(a, b game.Card) int {
+		return rules.ScoreCard(b, round) - rules.ScoreCard(a, round)
+	})
+
+	used := make([]bool, len(cards))
+	pairs := make([][]game.Card, 0)
+
+	for i, card := range cards {
+		if used[i] || card.IsWild(round) {
+			continue
+		}
+
+		for j := i + 1; j < len(cards); j++ {
+			other := cards[j]
+
+			if used[j] || other.IsWild(round) {
+				continue
+			}
+
+			gap := card.Number - other.Number
+			if card.Number == other.Number || (card.Suite == other.Suite && gap >= -2 && gap <= 2) {
+				used[i], used[j] = true, true
+				pairs = append(pairs, []game.Card{card, other})
+
+				break
+			}
+		}
+	}
+
+	singles := make([]game.Card, 0)
+	for i, card := range cards {
+		if !used[i] {
+			singles = append(singles, card)
+		}
+	}
+
+	return pairs, singles
+}
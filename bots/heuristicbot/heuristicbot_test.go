@@ -0,0 +1,232 @@
+package heuristicbot
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/engine"
+)
+
+// sequences need at least 4 cards
+var longRules = func() game.RuleSet {
+	long := game.OfficialRules
+	long.Name = "long"
+	long.MinSequenceLength = 4
+
+	return long
+}()
+
+func TestHeuristicBotDraw(t *testing.T) {
+
+	cases := []struct {
+		Name     string
+		Hand     string
+		Discard  string
+		Round    int
+		Weights  Weights
+		Rules    *game.RuleSet
+		Expected bots.Stack
+	}{
+		{
+			Name:     "completes a set",
+			Hand:     "9-R:9-B:4-X:12-Y",
+			Discard:  "9-Y",
+			Round:    4,
+			Weights:  DefaultWeights,
+			Expected: bots.StackDiscard,
+		},
+		{
+			Name:     "high card",
+			Hand:     "9-R:9-B:10-B:4-X",
+			Discard:  "13-Y",
+			Round:    4,
+			Weights:  DefaultWeights,
+			Expected: bots.StackDeck,
+		},
+		{
+			Name:     "goes out with a wild",
+			Hand:     "9-R:9-B:12-Y:12-G",
+			Discard:  "*",
+			Round:    4,
+			Weights:  DefaultWeights,
+			Expected: bots.StackDiscard,
+		},
+		{
+			Name:     "empty discard pile",
+			Hand:     "9-R:9-B:10-B:4-X",
+			Discard:  "",
+			Round:    4,
+			Weights:  DefaultWeights,
+			Expected: bots.StackDeck,
+		},
+		{
+			Name:     "completes a set with the official rules",
+			Hand:     "9-R:9-B:4-X:12-Y",
+			Discard:  "9-Y",
+			Round:    4,
+			Weights:  DefaultWeights,
+			Rules:    &game.OfficialRules,
+			Expected: bots.StackDiscard,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			res, err := NewHeuristicBot(c.Weights).Draw(bots.BotRequest{
+				Action:      bots.ActionDraw,
+				Hand:        game.MustDecodeSequence(c.Hand),
+				Discard:     game.MustDecodeSequence(c.Discard),
+				Round:       c.Round,
+				PlayerCount: 2,
+				Rules:       c.Rules,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, c.Expected, res.Stack)
+		})
+	}
+}
+
+func TestHeuristicBotDiscard(t *testing.T) {
+
+	cases := []struct {
+		Name     string
+		Hand     string
+		Round    int
+		LastTurn bool
+		Weights  Weights
+		Rules    *game.RuleSet
+		Expected string
+		Flop     bool
+	}{
+		{
+			Name:     "goes out",
+			Hand:     "9-R:9-B:9-Y:13-X",
+			Round:    3,
+			Weights:  DefaultWeights,
+			Expected: "13-X",
+			Flop:     true,
+		},
+		{
+			Name:     "keeps the pair",
+			Hand:     "9-R:9-B:12-Y:4-X:6-G",
+			Round:    4,
+			Weights:  DefaultWeights,
+			Expected: "12-Y",
+		},
+		{
+			Name:     "keeps the set over the run",
+			Hand:     "9-R:9-B:12-Y:11-Y:4-X:6-G",
+			Round:    5,
+			Weights:  Weights{Partial: 1, SetBias: 1},
+			Expected: "12-Y",
+		},
+		{
+			Name:     "keeps the run over the set",
+			Hand:     "9-R:9-B:12-Y:11-Y:4-X:6-G",
+			Round:    5,
+			Weights:  Weights{Partial: 1, SetBias: -1},
+			Expected: "9-R",
+		},
+		{
+			Name:     "sheds the pair on the last turn",
+			Hand:     "12-R:12-B:9-Y:4-X",
+			Round:    3,
+			LastTurn: true,
+			Weights:  Weights{Partial: 1, LastTurnAggression: 1},
+			Expected: "12-R",
+		},
+		{
+			Name:     "goes out with the official rules",
+			Hand:     "9-R:9-B:9-Y:13-X",
+			Round:    3,
+			Weights:  DefaultWeights,
+			Rules:    &game.OfficialRules,
+			Expected: "13-X",
+			Flop:     true,
+		},
+		{
+			Name:     "keeps the pair with the official rules",
+			Hand:     "9-R:9-B:12-Y:4-X:6-G",
+			Round:    4,
+			Weights:  DefaultWeights,
+			Rules:    &game.OfficialRules,
+			Expected: "12-Y",
+		},
+		{
+			Name:     "a set of 3 is too short",
+			Hand:     "9-R:9-B:9-Y:13-X:5-G",
+			Round:    4,
+			Weights:  DefaultWeights,
+			Rules:    &longRules,
+			Expected: "13-X",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			res, err := NewHeuristicBot(c.Weights).Discard(bots.BotRequest{
+				Action:      bots.ActionDiscard,
+				Hand:        game.MustDecodeSequence(c.Hand),
+				Discard:     game.MustDecodeSequence("7-G"),
+				Round:       c.Round,
+				LastTurn:    c.LastTurn,
+				PlayerCount: 2,
+				Rules:       c.Rules,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, c.Expected, res.Card)
+			assert.Equal(t, c.Flop, res.Flop)
+
+			// every card but the discard is in the sequences
+			assert.Len(t, slices.Concat(res.Sequences...), len(strings.Split(c.Hand, ":"))-1)
+		})
+	}
+}
+
+func TestWeights(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "weights.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"setBias": -0.5, "drawThreshold": 3}`), 0644))
+
+	w, err := LoadWeights(path)
+	require.NoError(t, err)
+
+	// weights missing from the file keep their default
+	expected := DefaultWeights
+	expected.SetBias = -0.5
+	expected.DrawThreshold = 3
+	assert.Equal(t, expected, w)
+
+	require.NoError(t, SaveWeights(path, w))
+
+	again, err := LoadWeights(path)
+	require.NoError(t, err)
+	assert.Equal(t, w, again)
+
+	_, err = LoadWeights(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestHeuristicBotMatch(t *testing.T) {
+
+	e := engine.NewEngine([]engine.Player{
+		{Name: "heuristicbot", Bot: NewHeuristicBot(DefaultWeights)},
+		{Name: "grugbot", Bot: grugbot.NewGrugBot()},
+		{Name: "heuristicbot", Bot: NewHeuristicBot(DefaultWeights)},
+	}, 1)
+
+	res, err := e.Play()
+
+	require.NoError(t, err)
+	assert.Len(t, res.Rounds, engine.LastRound-engine.FirstRound+1)
+}
@@ -0,0 +1,68 @@
+package heuristicbot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Weights are how much heuristicbot values sets over runs, partial sequences, wilds and high cards when it
+// arranges its hand, and when it takes the discard pile or plays for the last turn, so they can be tuned without code changes
+// they are kept in a json file e.g. {"setBias": 0.2, "partial": 0.4, ...}
+
+type Weights struct {
+	// positive values favour sets over runs, both for tied arrangements and for the partial sequences kept
+	SetBias float64 `json:"setBias"`
+	// fraction of a partial sequence's penalty which is forgiven, for the chance it is completed
+	Partial float64 `json:"partial"`
+	// points each wild in the hand is worth, so the bot picks them up and holds on to spare ones
+	WildHoarding float64 `json:"wildHoarding"`
+	// extra cost for each point above 7 of a card left over, so high cards are shed first
+	HighCardShedding float64 `json:"highCardShedding"`
+	// fraction of the credit for partial sequences dropped on the last turn, or when an opponent is likely to go out
+	LastTurnAggression float64 `json:"lastTurnAggression"`
+	// chance of an opponent going out at which the bot plays as though it is its last turn
+	DamageControl float64 `json:"damageControl"`
+	// points the top of the discard pile must save before the bot takes it over an unknown card
+	DrawThreshold float64 `json:"drawThreshold"`
+}
+
+var DefaultWeights = Weights{
+	SetBias:            0.2,
+	Partial:            0.4,
+	WildHoarding:       2,
+	HighCardShedding:   0.2,
+	LastTurnAggression: 1,
+	DamageControl:      0.35,
+	DrawThreshold:      1,
+}
+
+// reads weights from a json file. weights missing from the file keep their default
+func LoadWeights(path string) (Weights, error) {
+	b, err := os.ReadFile(path)
+
+	if err != nil {
+		return Weights{}, fmt.Errorf("unable to read weights: %w", err)
+	}
+
+	w := DefaultWeights
+	if err := json.Unmarshal(b, &w); err != nil {
+		return Weights{}, fmt.Errorf("unable to decode weights: %w", err)
+	}
+
+	return w, nil
+}
+
+func SaveWeights(path string, w Weights) error {
+	b, err := json.MarshalIndent(w, "", "  ")
+
+	if err != nil {
+		return fmt.Errorf("unable to encode weights: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(b, '\n'), 0644); err != nil {
+		return fmt.Errorf("unable to write weights: %w", err)
+	}
+
+	return nil
+}
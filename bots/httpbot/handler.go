@@ -0,0 +1,77 @@
+package httpbot
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/timtatt/fivecrowns/bots"
+)
+
+// serves a bot over the HTTP spec in the README
+func NewHandler(botName string, bot bots.Bot) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		slog.Info("recieved request", "bot", botName)
+
+		defer req.Body.Close()
+
+		var botReq bots.BotRequest
+		err := json.NewDecoder(req.Body).Decode(&botReq)
+
+		if err != nil {
+			slog.Error("unable to unmarshal request", "err", err)
+			res.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// the empty string decodes to the nil card, which is never in a hand or the discard pile
+		if err := validateCards(botReq); err != nil {
+			slog.Error("invalid cards in request", "err", err)
+			http.Error(res, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var botRes interface{}
+		slog.Info("received request", "action", botReq.Action, "bot", botName, "req", botReq)
+		switch botReq.Action {
+		case bots.ActionScore:
+			botRes, err = bot.Score(botReq)
+		case bots.ActionDiscard:
+			botRes, err = bot.Discard(botReq)
+		case bots.ActionDraw:
+			botRes, err = bot.Draw(botReq)
+		}
+		slog.Info("calculated response", "action", botReq.Action, "bot", botName, "res", botRes)
+
+		if err != nil {
+			slog.Error("failed to get bot response", "err", err)
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		err = json.NewEncoder(res).Encode(botRes)
+
+		if err != nil {
+			slog.Error("failed to encode bot response", "err", err)
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func validateCards(req bots.BotRequest) error {
+	for _, card := range req.Hand {
+		if !card.Valid() {
+			return fmt.Errorf("invalid card in hand: %q", card.Encode())
+		}
+	}
+
+	for _, card := range req.Discard {
+		if !card.Valid() {
+			return fmt.Errorf("invalid card in discard pile: %q", card.Encode())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package httpbot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/timtatt/fivecrowns/bots"
+)
+
+// HttpBot plays through a remote bot which implements the HTTP spec in the README
+// each request is POSTed as json to the bot's url and retried if the bot is unavailable
+
+type Config struct {
+	// timeout for a single attempt
+	Timeout time.Duration
+	// number of times to retry after the first attempt fails
+	Retries int
+	// time to wait before each retry, multiplied by the attempt number
+	Backoff time.Duration
+}
+
+var DefaultConfig = Config{
+	Timeout: 5 * time.Second,
+	Retries: 2,
+	Backoff: 100 * time.Millisecond,
+}
+
+type httpBot struct {
+	url    string
+	config Config
+	client *http.Client
+}
+
+func NewHTTPBot(url string, config Config) bots.Bot {
+	return &httpBot{
+		url:    url,
+		config: config,
+		client: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+func (b *httpBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	req.Action = bots.ActionDraw
+
+	var res bots.DrawResponse
+	err := b.post(req, &res)
+
+	return res, err
+}
+
+func (b *httpBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	req.Action = bots.ActionDiscard
+
+	var res bots.DiscardResponse
+	err := b.post(req, &res)
+
+	return res, err
+}
+
+func (b *httpBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	req.Action = bots.ActionScore
+
+	var res bots.ScoreResponse
+	err := b.post(req, &res)
+
+	return res, err
+}
+
+// errors which will not be fixed by trying again
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+func (b *httpBot) post(req bots.BotRequest, res any) error {
+	body, err := json.Marshal(req)
+
+	if err != nil {
+		return fmt.Errorf("unable to encode request: %w", err)
+	}
+
+	var errs error
+	for attempt := 0; attempt <= b.config.Retries; attempt++ {
+		if attempt > 0 {
+			slog.Info("retrying bot request", "url", b.url, "attempt", attempt)
+			time.Sleep(time.Duration(attempt) * b.config.Backoff)
+		}
+
+		err = b.attempt(body, res)
+
+		if err == nil {
+			return nil
+		}
+
+		errs = errors.Join(errs, err)
+
+		var permErr *permanentError
+		if errors.As(err, &permErr) {
+			break
+		}
+	}
+
+	return fmt.Errorf("bot request to %s failed: %w", b.url, errs)
+}
+
+func (b *httpBot) attempt(body []byte, res any) error {
+	httpRes, err := b.client.Post(b.url, "application/json", bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	defer httpRes.Body.Close()
+
+	if httpRes.StatusCode >= 500 {
+		return fmt.Errorf("unexpected status: %s", httpRes.Status)
+	} else if httpRes.StatusCode != http.StatusOK {
+		return &permanentError{fmt.Errorf("unexpected status: %s", httpRes.Status)}
+	}
+
+	if err := json.NewDecoder(httpRes.Body).Decode(res); err != nil {
+		return &permanentError{fmt.Errorf("unable to decode response: %w", err)}
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package httpbot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+func TestHTTPBot(t *testing.T) {
+
+	server := httptest.NewServer(NewHandler("grugbot", grugbot.NewGrugBot()))
+	defer server.Close()
+
+	bot := NewHTTPBot(server.URL, DefaultConfig)
+
+	req := bots.BotRequest{
+		Hand:    game.MustDecodeSequence("9-R:10-R:5-X:8-R:6-B:8-B:11-R:11-Y:4-Y"),
+		Round:   9,
+		Discard: game.MustDecodeSequence("11-R"),
+	}
+
+	draw, err := bot.Draw(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, bots.ActionDraw, draw.Action)
+	assert.Equal(t, bots.StackDiscard, draw.Stack)
+
+	score, err := bot.Score(bots.BotRequest{
+		Hand:  game.MustDecodeSequence("5-B:*:5-R:4-B:6-B"),
+		Round: 10,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4-B:5-B:6-B:*", "5-R"}, game.FlattenSequences(score.Sequences))
+}
+
+func TestHTTPBotRetries(t *testing.T) {
+
+	handler := NewHandler("grugbot", grugbot.NewGrugBot())
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		attempts += 1
+
+		if attempts == 1 {
+			res.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		handler(res, req)
+	}))
+	defer server.Close()
+
+	bot := NewHTTPBot(server.URL, Config{
+		Timeout: time.Second,
+		Retries: 2,
+	})
+
+	_, err := bot.Score(bots.BotRequest{
+		Hand:  game.MustDecodeSequence("5-B:*:5-R:4-B:6-B"),
+		Round: 10,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestHTTPBotDoesNotRetryBadRequests(t *testing.T) {
+
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		attempts += 1
+		res.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	bot := NewHTTPBot(server.URL, Config{
+		Timeout: time.Second,
+		Retries: 2,
+	})
+
+	_, err := bot.Draw(bots.BotRequest{})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestHandlerRejectsInvalidCards(t *testing.T) {
+
+	handler := NewHandler("grugbot", grugbot.NewGrugBot())
+
+	cases := []struct {
+		Name     string
+		Body     string
+		Expected int
+	}{
+		{
+			Name:     "valid",
+			Body:     `{"action":"draw","round":3,"hand":["9-R","10-R","4-Y"],"discard":["11-R"]}`,
+			Expected: http.StatusOK,
+		},
+		{
+			// the empty string decodes to the nil card
+			Name:     "empty card in hand",
+			Body:     `{"action":"draw","round":3,"hand":["9-R","","4-Y"],"discard":["11-R"]}`,
+			Expected: http.StatusBadRequest,
+		},
+		{
+			Name:     "empty card in discard pile",
+			Body:     `{"action":"draw","round":3,"hand":["9-R","10-R","4-Y"],"discard":[""]}`,
+			Expected: http.StatusBadRequest,
+		},
+		{
+			Name:     "card not in the deck",
+			Body:     `{"action":"draw","round":3,"hand":["9-R","10-R","14-R"],"discard":["11-R"]}`,
+			Expected: http.StatusBadRequest,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			handler(res, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(c.Body)))
+
+			assert.Equal(t, c.Expected, res.Code)
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package ismctsbot
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/simulation"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/engine"
+)
+
+// Ismctsbot runs an information set monte carlo tree search over the draws and discards of every seat
+// each iteration deals the hidden cards at random and walks the tree, picking moves which have done well for
+// the seat making them, then plays the round out with a greedy policy
+
+type Config struct {
+	// number of iterations of the search for each decision
+	Iterations int
+	// stops the search early once the time is up. at least one iteration is always run. zero means no limit
+	Budget time.Duration
+	// number of turns each player takes before the hands are scored
+	Depth int
+	// number of discards each seat considers, taken from those which leave the lowest penalty
+	Candidates int
+	// how much the search favours moves which have been tried less
+	Exploration float64
+	// seed for dealing the hidden cards, so decisions can be reproduced
+	Seed uint64
+}
+
+var DefaultConfig = Config{
+	Iterations:  400,
+	Depth:       3,
+	Candidates:  3,
+	Exploration: 0.7,
+	Seed:        1,
+}
+
+type ismctsBot struct {
+	config Config
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func NewISMCTSBot(config Config) bots.Bot {
+	return &ismctsBot{
+		config: config,
+		rand:   rand.New(rand.NewPCG(config.Seed, 0)),
+	}
+}
+
+func (b *ismctsBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	rules := req.RuleSet()
+	seqs := rules.OptimalArrangement(req.Hand, req.Round).Hand()
+
+	return bots.ScoreResponse{
+		Action:    req.Action,
+		Flop:      rules.CanFlop(seqs, req.Round),
+		Sequences: game.EncodeSequences(seqs),
+	}, nil
+}
+
+func (b *ismctsBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	t, err := table(req)
+
+	if err != nil {
+		return bots.DrawResponse{}, err
+	}
+
+	if len(t.Discard) == 0 {
+		return bots.DrawResponse{
+			Action: req.Action,
+			Stack:  bots.StackDeck,
+		}, nil
+	}
+
+	root := b.search(t, phaseDraw, []move{{stack: bots.StackDeck}, {stack: bots.StackDiscard}})
+	best := root.best()
+
+	slog.Info("searched draws", "stack", best.move.stack, "visits", best.visits, "iterations", root.visits)
+
+	return bots.DrawResponse{
+		Action: req.Action,
+		Stack:  best.move.stack,
+	}, nil
+}
+
+func (b *ismctsBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	t, err := table(req)
+
+	if err != nil {
+		return bots.DiscardResponse{}, err
+	} else if len(t.Hand) == 0 {
+		return bots.DiscardResponse{}, errors.New("cannot discard from an empty hand")
+	}
+
+	exact := func(rules game.RuleSet, round int, hand []game.Card) int {
+		return rules.OptimalArrangement(hand, round).Penalty
+	}
+
+	moves := discards(t.Rules, t.Round, t.Hand, b.config.Candidates, exact)
+	card := moves[0].card
+
+	// there is no point searching when the bot can go out or the round is over after this turn
+	if exact(t.Rules, t.Round, remove(t.Hand, card)) > 0 && !t.LastTurn && len(moves) > 1 {
+		root := b.search(t, phaseDiscard, moves)
+		card = root.best().move.card
+
+		slog.Info("searched discards", "card", card.Encode(), "visits", root.best().visits, "iterations", root.visits)
+	}
+
+	seqs := t.Rules.OptimalArrangement(remove(t.Hand, card), t.Round).Hand()
+
+	return bots.DiscardResponse{
+		Flop:      t.Rules.CanFlop(seqs, req.Round),
+		Sequences: game.EncodeSequences(seqs),
+		Action:    bots.ActionDiscard,
+		Card:      card.Encode(),
+	}, nil
+}
+
+func table(req bots.BotRequest) (simulation.Table, error) {
+	if req.PlayerCount < engine.MinPlayers || req.PlayerCount > engine.MaxPlayers {
+		return simulation.Table{}, fmt.Errorf("unable to search a table of %d players, must be between %d and %d", req.PlayerCount, engine.MinPlayers, engine.MaxPlayers)
+	}
+
+	return simulation.NewTable(req), nil
+}
+
+// builds a search tree from the bot's decision, returning the root
+func (b *ismctsBot) search(t simulation.Table, phase phase, moves []move) *node {
+	b.mu.Lock()
+	r := rand.New(rand.NewPCG(b.rand.Uint64(), 0))
+	b.mu.Unlock()
+
+	turns := b.config.Depth * t.Players
+	if t.LastTurn {
+		turns = 1
+	}
+
+	root := &node{}
+	start := time.Now()
+
+	for i := range max(b.config.Iterations, 1) {
+		if i > 0 && b.config.Budget > 0 && time.Since(start) > b.config.Budget {
+			break
+		}
+
+		p := &playout{
+			state: t.Sample(r),
+			phase: phase,
+			turns: turns,
+		}
+
+		iterate(root, p, moves, b.config, r)
+		root.visits += 1
+	}
+
+	return root
+}
+
+func remove(hand []game.Card, card game.Card) []game.Card {
+	idx := slices.Index(hand, card)
+
+	return slices.Delete(slices.Clone(hand), idx, idx+1)
+}
@@ -0,0 +1,182 @@
+package ismctsbot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/engine"
+)
+
+func TestISMCTSBotDraw(t *testing.T) {
+
+	cases := []struct {
+		Hand     string
+		Discard  string
+		Round    int
+		Expected bots.Stack
+	}{
+		{
+			// completes a set
+			Hand:     "9-R:9-B:4-X:12-Y",
+			Discard:  "9-Y",
+			Round:    4,
+			Expected: bots.StackDiscard,
+		},
+		{
+			Hand:     "9-R:9-B:6-X:12-Y",
+			Discard:  "*",
+			Round:    4,
+			Expected: bots.StackDiscard,
+		},
+		{
+			// a high card which does not fit the hand
+			Hand:     "9-R:9-B:10-B:4-X",
+			Discard:  "13-Y",
+			Round:    4,
+			Expected: bots.StackDeck,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Hand, func(t *testing.T) {
+			res, err := NewISMCTSBot(DefaultConfig).Draw(bots.BotRequest{
+				Action:      bots.ActionDraw,
+				Hand:        game.MustDecodeSequence(c.Hand),
+				Discard:     game.MustDecodeSequence(c.Discard),
+				Round:       c.Round,
+				PlayerCount: 2,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, c.Expected, res.Stack)
+		})
+	}
+}
+
+func TestISMCTSBotDiscard(t *testing.T) {
+
+	cases := []struct {
+		Hand     string
+		Round    int
+		Expected string
+		Flop     bool
+	}{
+		{
+			Hand:     "9-R:9-B:9-Y:13-X",
+			Round:    3,
+			Expected: "13-X",
+			Flop:     true,
+		},
+		{
+			// keeps the pair which could become a set
+			Hand:     "9-R:9-B:12-Y:4-X:5-X",
+			Round:    4,
+			Expected: "12-Y",
+		},
+		{
+			Hand:     "3-B:4-B:5-B:6-B:10-R",
+			Round:    7,
+			Expected: "10-R",
+			Flop:     true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Hand, func(t *testing.T) {
+			res, err := NewISMCTSBot(DefaultConfig).Discard(bots.BotRequest{
+				Action:      bots.ActionDiscard,
+				Hand:        game.MustDecodeSequence(c.Hand),
+				Discard:     game.MustDecodeSequence("7-G"),
+				Round:       c.Round,
+				PlayerCount: 2,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, c.Expected, res.Card)
+			assert.Equal(t, c.Flop, res.Flop)
+		})
+	}
+}
+
+func TestISMCTSBotPlayerCount(t *testing.T) {
+
+	cases := []struct {
+		PlayerCount int
+		Ok          bool
+	}{
+		{PlayerCount: 1, Ok: false},
+		{PlayerCount: 2, Ok: true},
+		{PlayerCount: 7, Ok: true},
+		{PlayerCount: 8, Ok: false},
+	}
+
+	config := DefaultConfig
+	config.Iterations = 50
+
+	for _, c := range cases {
+		_, err := NewISMCTSBot(config).Draw(bots.BotRequest{
+			Action:      bots.ActionDraw,
+			Hand:        game.MustDecodeSequence("9-R:9-B:10-B:4-X:3-G:12-R:13-Y:6-Y:8-G:11-B:5-Y"),
+			Discard:     game.MustDecodeSequence("13-G"),
+			Round:       11,
+			PlayerCount: c.PlayerCount,
+		})
+
+		assert.Equal(t, c.Ok, err == nil, c.PlayerCount)
+	}
+}
+
+func TestISMCTSBotIterations(t *testing.T) {
+	config := DefaultConfig
+	config.Iterations = 200
+
+	b := NewISMCTSBot(config).(*ismctsBot)
+
+	tbl, err := table(bots.BotRequest{
+		Hand:        game.MustDecodeSequence("9-R:9-B:4-X:12-Y"),
+		Discard:     game.MustDecodeSequence("9-Y"),
+		Round:       4,
+		PlayerCount: 3,
+	})
+	require.NoError(t, err)
+
+	root := b.search(tbl, phaseDraw, []move{{stack: bots.StackDeck}, {stack: bots.StackDiscard}})
+
+	assert.Equal(t, 200, root.visits)
+	assert.Equal(t, 200, root.children[0].visits+root.children[1].visits)
+
+	// every seat has made a move somewhere in the tree
+	seats := make(map[int]bool)
+
+	var walk func(n *node)
+	walk = func(n *node) {
+		for _, c := range n.children {
+			seats[c.seat] = true
+			walk(c)
+		}
+	}
+
+	walk(root)
+
+	assert.Len(t, seats, 3)
+}
+
+func TestISMCTSBotMatch(t *testing.T) {
+	config := DefaultConfig
+	config.Iterations = 50
+
+	e := engine.NewEngine([]engine.Player{
+		{Name: "ismctsbot", Bot: NewISMCTSBot(config)},
+		{Name: "grugbot", Bot: grugbot.NewGrugBot()},
+		{Name: "ismctsbot", Bot: NewISMCTSBot(config)},
+	}, 1)
+
+	res, err := e.Play()
+
+	require.NoError(t, err)
+	assert.Len(t, res.Rounds, engine.LastRound-engine.FirstRound+1)
+}
@@ -0,0 +1,199 @@
+package ismctsbot
+
+import (
+	"cmp"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/simulation"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+// the search tree is shared by every determinization, so a node stands for all of the deals which reach it
+// each node keeps the reward of the seat which made the move into it, so opponents play to lower their own penalty
+
+// a move is either a draw from a stack, or a discard of a card
+type move struct {
+	stack bots.Stack
+	card  game.Card
+}
+
+type node struct {
+	move move
+	// seat which made the move
+	seat     int
+	children []*node
+	visits   int
+	// number of times the move could have been picked. a move is not available in every determinization
+	avail  int
+	reward float64
+}
+
+func (n *node) child(m move) *node {
+	for _, c := range n.children {
+		if c.move == m {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// the child with the most visits, which is the most trusted move
+func (n *node) best() *node {
+	return slices.MaxFunc(n.children, func(a, b *node) int {
+		return cmp.Or(cmp.Compare(a.visits, b.visits), cmp.Compare(a.reward, b.reward))
+	})
+}
+
+type phase int
+
+const (
+	phaseDraw phase = iota
+	phaseDiscard
+)
+
+// a determinization being played through the tree
+type playout struct {
+	state *simulation.State
+	seat  int
+	phase phase
+	// turns left before the hands are scored
+	turns int
+}
+
+func (p *playout) terminal() bool {
+	return p.turns <= 0 || (p.phase == phaseDraw && p.seat == p.state.WentOut)
+}
+
+// the moves the seat to play can make, pruning discards to those which leave the lowest penalty
+func (p *playout) moves(candidates int) []move {
+	if p.phase == phaseDraw {
+		moves := []move{{stack: bots.StackDeck}}
+
+		if len(p.state.Discard) > 0 {
+			moves = append(moves, move{stack: bots.StackDiscard})
+		}
+
+		return moves
+	}
+
+	return discards(p.state.Rules, p.state.Round, p.state.Hands[p.seat], candidates, simulation.Penalty)
+}
+
+func (p *playout) apply(m move) {
+	if p.phase == phaseDraw {
+		p.state.Draw(p.seat, m.stack)
+		p.phase = phaseDiscard
+
+		return
+	}
+
+	p.state.DiscardCard(p.seat, m.card)
+	p.seat = (p.seat + 1) % len(p.state.Hands)
+	p.phase = phaseDraw
+	p.turns -= 1
+}
+
+// plays the rest of the determinization with the rollout policy and returns the reward for each seat
+func (p *playout) finish() []float64 {
+	if !p.terminal() {
+		if p.phase == phaseDiscard {
+			p.apply(move{card: simulation.Worst(p.state.Rules, p.state.Round, p.state.Hands[p.seat], p.state.WentOut != -1)})
+		}
+
+		p.state.Rollout(p.seat, p.turns)
+	}
+
+	rewards := make([]float64, len(p.state.Hands))
+
+	for seat := range rewards {
+		rewards[seat] = reward(p.state.Penalty(seat))
+	}
+
+	return rewards
+}
+
+// penalties are scaled between 0 and 1 so the exploration constant does not depend on the round
+const maxPenalty = 50.0
+
+func reward(penalty int) float64 {
+	return 1 - min(float64(penalty), maxPenalty)/maxPenalty
+}
+
+// returns the distinct discards which leave the lowest penalty, lowest first
+func discards(rules game.RuleSet, round int, hand []game.Card, candidates int, penalty func(rules game.RuleSet, round int, hand []game.Card) int) []move {
+	options := simulation.Discards(rules, round, hand, candidates, penalty)
+	moves := make([]move, 0, len(options))
+
+	for _, option := range options {
+		moves = append(moves, move{card: option.Card})
+	}
+
+	return moves
+}
+
+// runs one iteration of the search from the root with a fresh determinization
+// the root moves are given, as the bot's real choices are worth pruning more carefully than those in the tree
+func iterate(root *node, p *playout, rootMoves []move, config Config, r *rand.Rand) {
+	path := []*node{root}
+	n := root
+
+	for !p.terminal() {
+		moves := rootMoves
+		if n != root {
+			moves = p.moves(config.Candidates)
+		}
+
+		unexplored := make([]move, 0)
+		for _, m := range moves {
+			if c := n.child(m); c == nil {
+				unexplored = append(unexplored, m)
+			} else {
+				c.avail += 1
+			}
+		}
+
+		if len(unexplored) > 0 {
+			m := unexplored[r.IntN(len(unexplored))]
+			c := &node{move: m, seat: p.seat, avail: 1}
+			n.children = append(n.children, c)
+
+			p.apply(m)
+			path = append(path, c)
+
+			break
+		}
+
+		n = selectChild(n, moves, config.Exploration)
+		p.apply(n.move)
+		path = append(path, n)
+	}
+
+	rewards := p.finish()
+
+	for _, n := range path[1:] {
+		n.visits += 1
+		n.reward += rewards[n.seat]
+	}
+}
+
+// picks the available child with the highest upper confidence bound
+func selectChild(n *node, moves []move, exploration float64) *node {
+	var best *node
+	bestValue := math.Inf(-1)
+
+	for _, m := range moves {
+		c := n.child(m)
+		value := c.reward/float64(c.visits) + exploration*math.Sqrt(math.Log(float64(c.avail))/float64(c.visits))
+
+		if value > bestValue {
+			best = c
+			bestValue = value
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,142 @@
+package learnedbot
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/training"
+)
+
+// Learnedbot scores hands with a model trained on self-play, rather than hand written rules
+// it keeps the hand the model predicts will end the round with the lowest score
+
+//go:embed model.json
+var defaultModel []byte
+
+// returns the model trained on grugbot self-play which ships with the bot
+func DefaultModel() training.Model {
+	m, err := training.LoadModel(bytes.NewReader(defaultModel))
+
+	if err != nil {
+		panic(fmt.Errorf("unable to load embedded model: %w", err))
+	}
+
+	return m
+}
+
+type learnedBot struct {
+	model training.Model
+}
+
+func NewLearnedBot(model training.Model) bots.Bot {
+	return &learnedBot{
+		model: model,
+	}
+}
+
+func (b *learnedBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	rules := req.RuleSet()
+	seqs := rules.OptimalArrangement(req.Hand, req.Round).Hand()
+
+	return bots.ScoreResponse{
+		Action:    req.Action,
+		Flop:      rules.CanFlop(seqs, req.Round),
+		Sequences: game.EncodeSequences(seqs),
+	}, nil
+}
+
+// takes the top of the discard pile when the best hand it makes is predicted to score lower than the current hand
+// the current hand stands in for drawing from the deck, as the model has learnt what unknown draws are worth
+func (b *learnedBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	hand := req.Hand
+	stack := bots.StackDeck
+
+	if len(req.Discard) > 0 && !req.Discard[0].IsNil() {
+		top := req.Discard[0]
+		_, fromDiscard := b.best(req, append(slices.Clone(hand), top))
+		fromDeck := b.predict(req, hand)
+
+		slog.Info("predicted scores", "discard", fromDiscard, "deck", fromDeck)
+
+		if fromDiscard < fromDeck {
+			stack = bots.StackDiscard
+		}
+	}
+
+	return bots.DrawResponse{
+		Action: req.Action,
+		Stack:  stack,
+	}, nil
+}
+
+func (b *learnedBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	hand := req.Hand
+
+	if len(hand) == 0 {
+		return bots.DiscardResponse{}, errors.New("cannot discard from an empty hand")
+	}
+
+	rules := req.RuleSet()
+	card, predicted := b.best(req, hand)
+	seqs := rules.OptimalArrangement(remove(hand, card), req.Round).Hand()
+
+	slog.Info("discarding card", "card", card.Encode(), "predicted", predicted)
+
+	return bots.DiscardResponse{
+		Flop:      rules.CanFlop(seqs, req.Round),
+		Sequences: game.EncodeSequences(seqs),
+		Action:    bots.ActionDiscard,
+		Card:      card.Encode(),
+	}, nil
+}
+
+// returns the discard which leaves the hand with the lowest predicted score. ties are broken by discarding the highest card
+// a hand which can go out is always kept, whatever the model says
+func (b *learnedBot) best(req bots.BotRequest, hand []game.Card) (game.Card, float64) {
+	var best game.Card
+	bestValue := 0.0
+	rules := req.RuleSet()
+
+	for i, card := range hand {
+		if slices.Index(hand, card) != i {
+			continue
+		}
+
+		rest := remove(hand, card)
+		value := math.Inf(-1)
+
+		if rules.OptimalArrangement(rest, req.Round).Penalty > 0 {
+			value = b.predict(req, rest)
+		}
+
+		if i == 0 || value < bestValue || (value == bestValue && rules.ScoreCard(card, req.Round) > rules.ScoreCard(best, req.Round)) {
+			best = card
+			bestValue = value
+		}
+	}
+
+	return best, bestValue
+}
+
+func (b *learnedBot) predict(req bots.BotRequest, hand []game.Card) float64 {
+	return b.model.Predict(training.Features(training.State{
+		Round:   req.Round,
+		Players: req.PlayerCount,
+		Turn:    req.Turn,
+		Hand:    hand,
+		Rules:   req.Rules,
+	}))
+}
+
+func remove(hand []game.Card, card game.Card) []game.Card {
+	idx := slices.Index(hand, card)
+
+	return slices.Delete(slices.Clone(hand), idx, idx+1)
+}
@@ -0,0 +1,139 @@
+package learnedbot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/engine"
+	"github.com/timtatt/fivecrowns/training"
+)
+
+// a model which predicts the penalty of the hand, plus a little for each leftover card
+func penaltyModel() training.Model {
+	weights := make([]float64, len(training.FeatureNames))
+	scaler := training.Scaler{
+		Mean:  make([]float64, len(training.FeatureNames)),
+		Scale: make([]float64, len(training.FeatureNames)),
+	}
+
+	for i, name := range training.FeatureNames {
+		scaler.Scale[i] = 1
+
+		switch name {
+		case "penalty":
+			weights[i] = 1
+		case "leftover":
+			weights[i] = 0.5
+		}
+	}
+
+	return &training.Linear{Scaler: scaler, Weights: weights}
+}
+
+func TestLearnedBotDraw(t *testing.T) {
+
+	cases := []struct {
+		Hand     string
+		Discard  string
+		Round    int
+		Expected bots.Stack
+	}{
+		{
+			// completes a set
+			Hand:     "9-R:9-B:4-X:12-Y",
+			Discard:  "9-Y",
+			Round:    4,
+			Expected: bots.StackDiscard,
+		},
+		{
+			// a high card which does not fit the hand
+			Hand:     "9-R:9-B:10-B:4-X",
+			Discard:  "13-Y",
+			Round:    4,
+			Expected: bots.StackDeck,
+		},
+		{
+			Hand:     "9-R:9-B:10-B:4-X",
+			Discard:  "",
+			Round:    4,
+			Expected: bots.StackDeck,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Hand+"/"+c.Discard, func(t *testing.T) {
+			res, err := NewLearnedBot(penaltyModel()).Draw(bots.BotRequest{
+				Action:      bots.ActionDraw,
+				Hand:        game.MustDecodeSequence(c.Hand),
+				Discard:     game.MustDecodeSequence(c.Discard),
+				Round:       c.Round,
+				PlayerCount: 2,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, c.Expected, res.Stack)
+		})
+	}
+}
+
+func TestLearnedBotDiscard(t *testing.T) {
+
+	cases := []struct {
+		Hand     string
+		Round    int
+		Expected string
+		Flop     bool
+	}{
+		{
+			Hand:     "9-R:9-B:9-Y:13-X",
+			Round:    3,
+			Expected: "13-X",
+			Flop:     true,
+		},
+		{
+			Hand:     "9-R:9-B:12-Y:4-X:5-X",
+			Round:    4,
+			Expected: "12-Y",
+		},
+		{
+			Hand:     "3-B:4-B:5-B:6-B:10-R",
+			Round:    7,
+			Expected: "10-R",
+			Flop:     true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Hand, func(t *testing.T) {
+			res, err := NewLearnedBot(penaltyModel()).Discard(bots.BotRequest{
+				Action:      bots.ActionDiscard,
+				Hand:        game.MustDecodeSequence(c.Hand),
+				Discard:     game.MustDecodeSequence("7-G"),
+				Round:       c.Round,
+				PlayerCount: 2,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, c.Expected, res.Card)
+			assert.Equal(t, c.Flop, res.Flop)
+		})
+	}
+}
+
+func TestLearnedBotMatch(t *testing.T) {
+
+	e := engine.NewEngine([]engine.Player{
+		{Name: "learnedbot", Bot: NewLearnedBot(DefaultModel())},
+		{Name: "grugbot", Bot: grugbot.NewGrugBot()},
+		{Name: "learnedbot", Bot: NewLearnedBot(DefaultModel())},
+	}, 1)
+
+	res, err := e.Play()
+
+	require.NoError(t, err)
+	assert.Len(t, res.Rounds, engine.LastRound-engine.FirstRound+1)
+}
@@ -0,0 +1,180 @@
+package montecarlobot
+
+import (
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/simulation"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+// Montecarlobot deals the cards it cannot see at random, consistent with its hand, the discard pile and
+// the cards opponents are known to have picked up, then plays the round out with a fast greedy policy
+// it picks the stack and discard with the lowest average penalty over all of the deals
+
+type Config struct {
+	// number of deals to simulate for each choice
+	Samples int
+	// stops sampling early once the time is up. at least one deal is always simulated. zero means no limit
+	Budget time.Duration
+	// number of turns each player takes in a simulation before the bot's hand is scored
+	Depth int
+	// number of discards to simulate, taken from those which leave the lowest penalty
+	Candidates int
+	// seed for dealing the hidden cards, so decisions can be reproduced
+	Seed uint64
+}
+
+var DefaultConfig = Config{
+	Samples:    32,
+	Depth:      4,
+	Candidates: 4,
+	Seed:       1,
+}
+
+type monteCarloBot struct {
+	config Config
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func NewMonteCarloBot(config Config) bots.Bot {
+	return &monteCarloBot{
+		config: config,
+		rand:   rand.New(rand.NewPCG(config.Seed, 0)),
+	}
+}
+
+func (b *monteCarloBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	rules := req.RuleSet()
+	seqs := rules.OptimalArrangement(req.Hand, req.Round).Hand()
+
+	return bots.ScoreResponse{
+		Action:    req.Action,
+		Flop:      rules.CanFlop(seqs, req.Round),
+		Sequences: game.EncodeSequences(seqs),
+	}, nil
+}
+
+func (b *monteCarloBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	t := simulation.NewTable(req)
+
+	if len(t.Discard) == 0 {
+		return bots.DrawResponse{
+			Action: req.Action,
+			Stack:  bots.StackDeck,
+		}, nil
+	}
+
+	// both stacks are played out against the same deals so the difference between them is not down to luck
+	stacks := []bots.Stack{bots.StackDeck, bots.StackDiscard}
+
+	means := b.simulate(len(stacks), func(s *simulation.State, choice int) int {
+		s.Draw(0, stacks[choice])
+		s.DiscardCard(0, simulation.Worst(s.Rules, s.Round, s.Hands[0], t.LastTurn))
+
+		if !t.LastTurn {
+			s.Rollout(1, b.config.Depth*len(s.Hands))
+		}
+
+		return s.Penalty(0)
+	}, t)
+
+	slog.Info("expected penalties", "deck", means[0], "discard", means[1])
+
+	stack := bots.StackDeck
+	if means[1] <= means[0] {
+		stack = bots.StackDiscard
+	}
+
+	return bots.DrawResponse{
+		Action: req.Action,
+		Stack:  stack,
+	}, nil
+}
+
+func (b *monteCarloBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	t := simulation.NewTable(req)
+
+	if len(t.Hand) == 0 {
+		return bots.DiscardResponse{}, errors.New("cannot discard from an empty hand")
+	}
+
+	candidates := b.candidates(t)
+
+	best := candidates[0]
+
+	// there is no point simulating when the bot can go out or the round is over after this turn
+	if best.Penalty > 0 && !t.LastTurn && len(candidates) > 1 {
+		means := b.simulate(len(candidates), func(s *simulation.State, choice int) int {
+			s.DiscardCard(0, candidates[choice].Card)
+			s.Rollout(1, b.config.Depth*len(s.Hands))
+
+			return s.Penalty(0)
+		}, t)
+
+		for i, c := range candidates {
+			slog.Debug("expected penalty", "card", c.Card.Encode(), "mean", means[i])
+		}
+
+		best = candidates[slices.Index(means, slices.Min(means))]
+	}
+
+	slog.Info("discarding card", "card", best.Card.Encode(), "penalty", best.Penalty)
+
+	seqs := t.Rules.OptimalArrangement(best.Rest, t.Round).Hand()
+
+	return bots.DiscardResponse{
+		Flop:      t.Rules.CanFlop(seqs, req.Round),
+		Sequences: game.EncodeSequences(seqs),
+		Action:    bots.ActionDiscard,
+		Card:      best.Card.Encode(),
+	}, nil
+}
+
+// returns the discards which leave the lowest penalty, lowest first
+func (b *monteCarloBot) candidates(t simulation.Table) []simulation.Discard {
+	exact := func(rules game.RuleSet, round int, hand []game.Card) int {
+		return rules.OptimalArrangement(hand, round).Penalty
+	}
+
+	return simulation.Discards(t.Rules, t.Round, t.Hand, b.config.Candidates, exact)
+}
+
+// plays each choice out against the same deals and returns the mean penalty of each
+func (b *monteCarloBot) simulate(choices int, play func(s *simulation.State, choice int) int, t simulation.Table) []float64 {
+	b.mu.Lock()
+	seeds := make([]uint64, max(b.config.Samples, 1))
+	for i := range seeds {
+		seeds[i] = b.rand.Uint64()
+	}
+	b.mu.Unlock()
+
+	start := time.Now()
+	totals := make([]float64, choices)
+	samples := 0
+
+	for _, seed := range seeds {
+		if samples > 0 && b.config.Budget > 0 && time.Since(start) > b.config.Budget {
+			break
+		}
+
+		for choice := range choices {
+			totals[choice] += float64(play(t.Sample(rand.New(rand.NewPCG(seed, 0))), choice))
+		}
+
+		samples += 1
+	}
+
+	for i := range totals {
+		totals[i] /= float64(samples)
+	}
+
+	return totals
+}
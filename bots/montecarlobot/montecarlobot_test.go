@@ -0,0 +1,137 @@
+package montecarlobot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/engine"
+)
+
+func TestMonteCarloBotDraw(t *testing.T) {
+
+	cases := []struct {
+		Hand     string
+		Discard  string
+		Round    int
+		Expected bots.Stack
+	}{
+		{
+			// completes a set
+			Hand:     "9-R:9-B:4-X:12-Y",
+			Discard:  "9-Y",
+			Round:    4,
+			Expected: bots.StackDiscard,
+		},
+		{
+			Hand:     "9-R:9-B:6-X:12-Y",
+			Discard:  "*",
+			Round:    4,
+			Expected: bots.StackDiscard,
+		},
+		{
+			// a high card which does not fit the hand
+			Hand:     "9-R:9-B:10-B:4-X",
+			Discard:  "13-Y",
+			Round:    4,
+			Expected: bots.StackDeck,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Hand, func(t *testing.T) {
+			res, err := NewMonteCarloBot(DefaultConfig).Draw(bots.BotRequest{
+				Action:      bots.ActionDraw,
+				Hand:        game.MustDecodeSequence(c.Hand),
+				Discard:     game.MustDecodeSequence(c.Discard),
+				Round:       c.Round,
+				PlayerCount: 2,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, c.Expected, res.Stack)
+		})
+	}
+}
+
+func TestMonteCarloBotDiscard(t *testing.T) {
+
+	cases := []struct {
+		Hand     string
+		Round    int
+		Expected string
+		Flop     bool
+	}{
+		{
+			Hand:     "9-R:9-B:9-Y:13-X",
+			Round:    3,
+			Expected: "13-X",
+			Flop:     true,
+		},
+		{
+			// keeps the pair which could become a set
+			Hand:     "9-R:9-B:12-Y:4-X:5-X",
+			Round:    4,
+			Expected: "12-Y",
+		},
+		{
+			Hand:     "3-B:4-B:5-B:6-B:10-R",
+			Round:    7,
+			Expected: "10-R",
+			Flop:     true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Hand, func(t *testing.T) {
+			res, err := NewMonteCarloBot(DefaultConfig).Discard(bots.BotRequest{
+				Action:      bots.ActionDiscard,
+				Hand:        game.MustDecodeSequence(c.Hand),
+				Discard:     game.MustDecodeSequence("7-G"),
+				Round:       c.Round,
+				PlayerCount: 2,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, c.Expected, res.Card)
+			assert.Equal(t, c.Flop, res.Flop)
+		})
+	}
+}
+
+func TestMonteCarloBotBudget(t *testing.T) {
+	config := DefaultConfig
+	config.Samples = 100000
+	config.Budget = 20 * time.Millisecond
+
+	start := time.Now()
+
+	_, err := NewMonteCarloBot(config).Draw(bots.BotRequest{
+		Action:      bots.ActionDraw,
+		Hand:        game.MustDecodeSequence("9-R:9-B:10-B:4-X:3-G:12-R:13-Y:6-Y:8-G:11-B"),
+		Discard:     game.MustDecodeSequence("13-G"),
+		Round:       10,
+		PlayerCount: 4,
+	})
+
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestMonteCarloBotMatch(t *testing.T) {
+
+	e := engine.NewEngine([]engine.Player{
+		{Name: "montecarlobot", Bot: NewMonteCarloBot(DefaultConfig)},
+		{Name: "grugbot", Bot: grugbot.NewGrugBot()},
+		{Name: "montecarlobot", Bot: NewMonteCarloBot(DefaultConfig)},
+	}, 1)
+
+	res, err := e.Play()
+
+	require.NoError(t, err)
+	assert.Len(t, res.Rounds, engine.LastRound-engine.FirstRound+1)
+}
@@ -0,0 +1,291 @@
+package simulation
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+// Simulation deals the cards a bot cannot see at random and plays the round out with a fast policy
+// seats are relative to the bot, which is always seat 0. seat 1 is the opponent to the left, who plays next
+
+// Table is what the bot can see of the round
+type Table struct {
+	Round   int
+	Players int
+	Hand    []game.Card
+	// top-most card is at index 0
+	Discard []game.Card
+	// cards each opponent is known to be holding, having picked them up off the discard pile
+	Known [][]game.Card
+	// cards which could be anywhere in the deck or an opponent's hand
+	Unknown []game.Card
+	// the bot's turn is the last one of the round
+	LastTurn bool
+	Rules    game.RuleSet
+}
+
+func NewTable(req bots.BotRequest) Table {
+	hand := slices.Clone(req.Hand)
+	discard := slices.Clone(req.Discard)
+
+	t := Table{
+		Round:    req.Round,
+		Players:  max(req.PlayerCount, 2),
+		Hand:     hand,
+		Discard:  discard,
+		LastTurn: req.LastTurn,
+		Rules:    req.RuleSet(),
+	}
+
+	t.Known = KnownCards(req.History, t.Players)
+
+	seen := slices.Concat(hand, discard)
+	for _, cards := range t.Known {
+		seen = append(seen, cards...)
+	}
+
+	t.Unknown = remaining(t.Rules.NewDeck().Cards(), seen)
+
+	return t
+}
+
+// works out which cards each opponent picked up off the discard pile and has not thrown away since
+func KnownCards(history []bots.OpponentTurn, players int) [][]game.Card {
+	known := make([][]game.Card, players)
+
+	for _, turn := range history {
+		if turn.Seat < 1 || turn.Seat >= players {
+			continue
+		}
+
+		if turn.Stack == bots.StackDiscard && !turn.Drawn.IsNil() {
+			known[turn.Seat] = append(known[turn.Seat], turn.Drawn)
+		}
+
+		if !turn.Discard.IsNil() {
+			if idx := slices.Index(known[turn.Seat], turn.Discard); idx != -1 {
+				known[turn.Seat] = slices.Delete(known[turn.Seat], idx, idx+1)
+			}
+		}
+	}
+
+	return known
+}
+
+// returns the cards which have not been seen, counting duplicates
+func remaining(cards []game.Card, seen []game.Card) []game.Card {
+	counts := make(map[game.Card]int)
+
+	for _, card := range seen {
+		counts[card] += 1
+	}
+
+	out := make([]game.Card, 0, len(cards))
+
+	for _, card := range cards {
+		if counts[card] > 0 {
+			counts[card] -= 1
+		} else {
+			out = append(out, card)
+		}
+	}
+
+	return out
+}
+
+// State is one possible deal of the hidden cards, part way through the round
+type State struct {
+	Round int
+	Hands [][]game.Card
+	// top-most card is last
+	Deck []game.Card
+	// top-most card is at index 0
+	Discard []game.Card
+	// seat of the player who went out, -1 while nobody has
+	WentOut int
+	Rules   game.RuleSet
+
+	rand *rand.Rand
+}
+
+// deals the unknown cards at random into the opponents' hands and the deck
+// the random source is kept to reshuffle the discard pile if the deck runs out
+func (t Table) Sample(r *rand.Rand) *State {
+	unknown := slices.Clone(t.Unknown)
+	r.Shuffle(len(unknown), func(i, j int) {
+		unknown[i], unknown[j] = unknown[j], unknown[i]
+	})
+
+	s := &State{
+		Round:   t.Round,
+		Hands:   make([][]game.Card, t.Players),
+		Discard: slices.Clone(t.Discard),
+		WentOut: -1,
+		Rules:   t.Rules,
+		rand:    r,
+	}
+
+	s.Hands[0] = slices.Clone(t.Hand)
+
+	for seat := 1; seat < t.Players; seat++ {
+		hand := slices.Clone(t.Known[seat])
+
+		for len(hand) < t.Round && len(unknown) > 0 {
+			hand = append(hand, unknown[len(unknown)-1])
+			unknown = unknown[:len(unknown)-1]
+		}
+
+		s.Hands[seat] = hand
+	}
+
+	s.Deck = unknown
+
+	return s
+}
+
+// draws for a seat, returning the card drawn
+func (s *State) Draw(seat int, stack bots.Stack) game.Card {
+	var card game.Card
+
+	if stack == bots.StackDiscard && len(s.Discard) > 0 {
+		card = s.Discard[0]
+		s.Discard = s.Discard[1:]
+	} else {
+		if len(s.Deck) == 0 && len(s.Discard) > 1 {
+			s.Deck = slices.Clone(s.Discard[1:])
+			s.Discard = s.Discard[:1]
+
+			s.rand.Shuffle(len(s.Deck), func(i, j int) {
+				s.Deck[i], s.Deck[j] = s.Deck[j], s.Deck[i]
+			})
+		}
+
+		if len(s.Deck) == 0 {
+			return game.Card{}
+		}
+
+		card = s.Deck[len(s.Deck)-1]
+		s.Deck = s.Deck[:len(s.Deck)-1]
+	}
+
+	s.Hands[seat] = append(s.Hands[seat], card)
+
+	return card
+}
+
+// discards a card for a seat, marking the seat as out if the rest of its hand is in valid sequences
+func (s *State) DiscardCard(seat int, card game.Card) {
+	hand := s.Hands[seat]
+	idx := slices.Index(hand, card)
+
+	s.Hands[seat] = slices.Delete(slices.Clone(hand), idx, idx+1)
+	s.Discard = slices.Insert(s.Discard, 0, card)
+
+	if s.WentOut == -1 && s.Penalty(seat) == 0 {
+		s.WentOut = seat
+	}
+}
+
+// plays a turn for a seat with the rollout policy
+func (s *State) PlayTurn(seat int) {
+	stack := bots.StackDeck
+
+	if len(s.Discard) > 0 && Wants(s.Rules, s.Round, s.Hands[seat], s.Discard[0]) {
+		stack = bots.StackDiscard
+	}
+
+	s.Draw(seat, stack)
+	s.DiscardCard(seat, Worst(s.Rules, s.Round, s.Hands[seat], s.WentOut != -1))
+}
+
+// plays turns with the rollout policy starting from a seat, until the round ends or the turns run out
+// returns true if the round ended
+func (s *State) Rollout(seat int, turns int) bool {
+	for range turns {
+		if seat == s.WentOut {
+			return true
+		}
+
+		s.PlayTurn(seat)
+		seat = (seat + 1) % len(s.Hands)
+	}
+
+	return seat == s.WentOut
+}
+
+// the penalty left in a seat's hand
+func (s *State) Penalty(seat int) int {
+	return Penalty(s.Rules, s.Round, s.Hands[seat])
+}
+
+// the rollout policy takes the top of the discard pile when it completes a sequence, the same as grugbot
+func Wants(rules game.RuleSet, round int, hand []game.Card, card game.Card) bool {
+	if card.IsWild(round) {
+		return true
+	}
+
+	seqs := Arrange(rules, round, append(slices.Clone(hand), card))
+
+	for _, seq := range seqs {
+		if slices.Contains(seq, card) {
+			return rules.ValidateSequence(seq, round) == nil
+		}
+	}
+
+	return false
+}
+
+// the rollout policy discards the highest card outside a sequence, the same as grugbot
+func Worst(rules game.RuleSet, round int, hand []game.Card, lastTurn bool) game.Card {
+	seqs := Arrange(rules, round, hand)
+
+	return grugbot.WorstCard(rules, round, seqs, lastTurn).Card
+}
+
+// Discard is a card which could be thrown away and the hand left behind
+type Discard struct {
+	Card    game.Card
+	Rest    []game.Card
+	Penalty int
+}
+
+// returns the distinct discards which leave the lowest penalty, lowest first. ties are broken by discarding the highest card
+// at most candidates are returned, and always at least one
+func Discards(rules game.RuleSet, round int, hand []game.Card, candidates int, penalty func(rules game.RuleSet, round int, hand []game.Card) int) []Discard {
+	options := make([]Discard, 0, len(hand))
+
+	for i, card := range hand {
+		if slices.Index(hand, card) != i {
+			continue
+		}
+
+		rest := slices.Delete(slices.Clone(hand), i, i+1)
+		options = append(options, Discard{Card: card, Rest: rest, Penalty: penalty(rules, round, rest)})
+	}
+
+	slices.SortStableFunc(options, func(a, b Discard) int {
+		return cmp.Or(
+			cmp.Compare(a.Penalty, b.Penalty),
+			cmp.Compare(rules.ScoreCard(b.Card, round), rules.ScoreCard(a.Card, round)),
+		)
+	})
+
+	return options[:min(len(options), max(candidates, 1))]
+}
+
+func Penalty(rules game.RuleSet, round int, hand []game.Card) int {
+	return rules.ScorePenalty(Arrange(rules, round, hand), round)
+}
+
+// arranges a hand with grugbot's greedy sequencing, which is fast enough to call on every simulated turn
+func Arrange(rules game.RuleSet, round int, hand []game.Card) [][]game.Card {
+	hand = slices.Clone(hand)
+	slices.SortFunc(hand, game.CompareCard)
+
+	return grugbot.FilterSequences(rules, round, hand, grugbot.FindSequences(round, hand))
+}
@@ -0,0 +1,75 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+func TestKnownCards(t *testing.T) {
+
+	known := KnownCards([]bots.OpponentTurn{
+		{Seat: 1, Turn: 1, Stack: bots.StackDiscard, Drawn: game.MustDecodeCard("9-R"), Discard: game.MustDecodeCard("13-B")},
+		{Seat: 2, Turn: 2, Stack: bots.StackDiscard, Drawn: game.MustDecodeCard("3-G"), Discard: game.MustDecodeCard("4-G")},
+		{Seat: 2, Turn: 4, Stack: bots.StackDeck, Discard: game.MustDecodeCard("3-G")},
+	}, 3)
+
+	assert.Equal(t, []game.Card{{Number: 9, Suite: game.SuiteRed}}, known[1])
+	assert.Empty(t, known[2])
+}
+
+func TestTableSample(t *testing.T) {
+
+	tbl := NewTable(bots.BotRequest{
+		Hand:        game.MustDecodeSequence("9-R:9-B:4-X"),
+		Discard:     game.MustDecodeSequence("7-G:5-Y"),
+		Round:       3,
+		PlayerCount: 3,
+		History: []bots.OpponentTurn{
+			{Seat: 1, Turn: 1, Stack: bots.StackDiscard, Drawn: game.MustDecodeCard("5-Y"), Discard: game.MustDecodeCard("7-G")},
+		},
+	})
+
+	// the 5-Y was discarded before the opponent picked it up, so one copy is in the pile and one is in their hand
+	assert.Len(t, tbl.Unknown, 116-3-2-1)
+
+	s := tbl.Sample(game.RoundRand(1, 3))
+
+	assert.Contains(t, s.Hands[1], game.Card{Number: 5, Suite: game.SuiteYellow})
+
+	total := len(s.Deck) + len(s.Discard)
+	for _, hand := range s.Hands {
+		assert.Len(t, hand, 3)
+		total += len(hand)
+	}
+
+	assert.Equal(t, 116, total)
+}
+
+func TestDiscards(t *testing.T) {
+
+	hand := game.MustDecodeSequence("3-R:4-R:6-R:13-B:13-B:9-G")
+
+	// the duplicate king is only considered once
+	discards := Discards(game.HouseRules, 7, hand, len(hand), Penalty)
+	require.Len(t, discards, 5)
+
+	for i, d := range discards {
+		assert.Equal(t, Penalty(game.HouseRules, 7, d.Rest), d.Penalty)
+		assert.Len(t, d.Rest, len(hand)-1)
+
+		if i > 0 {
+			assert.LessOrEqual(t, discards[i-1].Penalty, d.Penalty)
+			assert.NotEqual(t, discards[i-1].Card, d.Card)
+		}
+	}
+
+	// throwing away a king leaves the least
+	assert.Equal(t, game.MustDecodeCard("13-B"), discards[0].Card)
+
+	// always at least one
+	assert.Len(t, Discards(game.HouseRules, 7, hand, 0, Penalty), 1)
+}
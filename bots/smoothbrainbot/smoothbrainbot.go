@@ -17,7 +17,7 @@ func (*smoothBrainBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
 
 	r := rand.Intn(2)
 	stack := bots.StackDeck
-	if r == 1 {
+	if r == 1 && len(req.Discard) > 0 {
 		stack = bots.StackDiscard
 	}
 
@@ -36,13 +36,13 @@ func (s *smoothBrainBot) Discard(req bots.BotRequest) (bots.DiscardResponse, err
 
 	for i, card := range req.Hand {
 		if i != discardIdx {
-			sequences = append(sequences, []string{card})
+			sequences = append(sequences, []string{card.Encode()})
 		}
 	}
 
 	return bots.DiscardResponse{
 		Action:    bots.ActionDiscard,
-		Card:      req.Hand[discardIdx],
+		Card:      req.Hand[discardIdx].Encode(),
 		Sequences: sequences,
 	}, nil
 }
@@ -52,11 +52,11 @@ func (s *smoothBrainBot) Score(req bots.BotRequest) (bots.ScoreResponse, error)
 	sequences := make([][]string, 0, len(req.Hand))
 
 	for _, card := range req.Hand {
-		sequences = append(sequences, []string{card})
+		sequences = append(sequences, []string{card.Encode()})
 	}
 
 	return bots.ScoreResponse{
-		Action:    bots.ActionDiscard,
+		Action:    bots.ActionScore,
 		Flop:      false,
 		Sequences: sequences,
 	}, nil
@@ -0,0 +1,124 @@
+package strategy
+
+import (
+	"math"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+// Strategy holds decisions which are shared between bots
+// the further a round goes, the more likely an opponent is to go out. once that is likely,
+// a bot is better off shedding its highest cards than waiting to complete sequences
+
+// chance of an opponent going out before the bot's next turn at which bots switch to damage control
+const DamageControlThreshold = 0.35
+
+// the chance of a player going out on their first turn of round 3, and how much it grows with each turn after
+// a hand rarely fits into sequences as dealt, so it takes a few draws before going out becomes likely
+const (
+	firstTurnHazard = 0.02
+	turnHazard      = 0.045
+	// the most likely an opponent is to go out on any one turn
+	maxHazard = 0.5
+)
+
+// estimates the chance of an opponent going out before the bot's next turn
+// each opponent is more likely to go out the more turns they have had, and the more cards they have picked up off the discard pile
+// the turns come from the history, or the turn number or deck count when there is no history
+func FlopChance(req bots.BotRequest) float64 {
+	if req.LastTurn {
+		return 1
+	}
+
+	opponents := max(req.PlayerCount-1, 1)
+
+	// turns taken by each opponent, counting a card picked up off the discard pile as an extra turn
+	turns := make([]int, opponents)
+
+	if len(req.History) > 0 {
+		for _, turn := range req.History {
+			if turn.Seat < 1 || turn.Seat > opponents {
+				continue
+			}
+
+			turns[turn.Seat-1] += 1
+
+			if turn.Stack == bots.StackDiscard {
+				turns[turn.Seat-1] += 1
+			}
+		}
+	} else {
+		// without a history, assume every opponent has had as many turns as the table has played
+		played := max(req.Turn-1, 0)
+
+		if req.Turn == 0 && req.DeckCount > 0 {
+			// every turn draws from the deck, unless the discard pile was taken
+			dealt := max(req.PlayerCount, 1)*req.Round + 1
+			played = max(req.RuleSet().DeckSize()-dealt-req.DeckCount, 0)
+		}
+
+		for i := range turns {
+			turns[i] = played / max(req.PlayerCount, 1)
+		}
+	}
+
+	missed := 1.0
+	for _, taken := range turns {
+		missed *= 1 - hazard(req.Round, taken+1)
+	}
+
+	return 1 - missed
+}
+
+// the chance of a player going out on their nth turn of the round
+// a bigger hand is less likely to fit into sequences as dealt
+func hazard(round int, turn int) float64 {
+	first := firstTurnHazard * 3 / float64(max(round, 3))
+
+	return math.Min(first+turnHazard*float64(turn-1), maxHazard)
+}
+
+// determines if the bot should stop building sequences and shed its high cards
+func DamageControl(req bots.BotRequest) bool {
+	return FlopChance(req) >= DamageControlThreshold
+}
+
+// Location is a card within a hand arranged into sequences
+type Location struct {
+	Card        game.Card
+	SequenceIdx int
+	CardIdx     int
+}
+
+// chooses the card which costs the most if an opponent goes out, out of the cards outside a valid sequence
+// cards in a partial sequence are protected by the chance that the round continues long enough to complete it
+// returns false if every card is part of a valid sequence
+func Shed(rules game.RuleSet, round int, seqs [][]game.Card, chance float64) (Location, bool) {
+	var shed Location
+	cost := -1.0
+
+	for i, seq := range seqs {
+		if rules.ValidateSequence(seq, round) == nil {
+			continue
+		}
+
+		weight := 1.0
+		if len(seq) >= 2 {
+			weight = chance
+		}
+
+		for j, card := range seq {
+			if c := weight * float64(rules.ScoreCard(card, round)); c > cost {
+				shed = Location{
+					Card:        card,
+					SequenceIdx: i,
+					CardIdx:     j,
+				}
+				cost = c
+			}
+		}
+	}
+
+	return shed, cost >= 0
+}
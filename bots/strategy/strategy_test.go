@@ -0,0 +1,157 @@
+package strategy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+func TestFlopChance(t *testing.T) {
+
+	early := bots.BotRequest{
+		Round:       10,
+		PlayerCount: 3,
+		Turn:        1,
+	}
+
+	late := bots.BotRequest{
+		Round:       10,
+		PlayerCount: 3,
+		Turn:        30,
+	}
+
+	assert.Less(t, FlopChance(early), FlopChance(late))
+	assert.False(t, DamageControl(early))
+	assert.True(t, DamageControl(late))
+
+	assert.Equal(t, 1.0, FlopChance(bots.BotRequest{Round: 10, PlayerCount: 3, LastTurn: true}))
+
+	// an opponent picking up off the discard pile is closer to going out
+	fromDeck := bots.BotRequest{
+		Round:       10,
+		PlayerCount: 2,
+		History: []bots.OpponentTurn{
+			{Seat: 1, Turn: 1, Stack: bots.StackDeck, Discard: game.MustDecodeCard("4-B")},
+		},
+	}
+
+	fromDiscard := bots.BotRequest{
+		Round:       10,
+		PlayerCount: 2,
+		History: []bots.OpponentTurn{
+			{Seat: 1, Turn: 1, Stack: bots.StackDiscard, Drawn: game.MustDecodeCard("5-B"), Discard: game.MustDecodeCard("4-B")},
+		},
+	}
+
+	assert.Less(t, FlopChance(fromDeck), FlopChance(fromDiscard))
+}
+
+func TestFlopChanceDeckCount(t *testing.T) {
+
+	// 116 cards, 4 dealt to each player and 1 to the discard pile
+	fresh := bots.BotRequest{Round: 4, PlayerCount: 3, DeckCount: 103}
+	// 30 cards drawn from the deck, 10 turns for each player
+	later := bots.BotRequest{Round: 4, PlayerCount: 3, DeckCount: 73}
+
+	assert.InDelta(t, FlopChance(bots.BotRequest{Round: 4, PlayerCount: 3, Turn: 1}), FlopChance(fresh), 1e-9)
+	assert.InDelta(t, FlopChance(bots.BotRequest{Round: 4, PlayerCount: 3, Turn: 31}), FlopChance(later), 1e-9)
+	assert.False(t, DamageControl(fresh))
+	assert.True(t, DamageControl(later))
+}
+
+func TestFlopChanceRound(t *testing.T) {
+
+	// a small hand is more likely to go out straight away
+	small := bots.BotRequest{Round: 3, PlayerCount: 2, Turn: 1}
+	big := bots.BotRequest{Round: 13, PlayerCount: 2, Turn: 1}
+
+	assert.Greater(t, FlopChance(small), FlopChance(big))
+}
+
+func TestDamageControlFullTable(t *testing.T) {
+
+	// the last seat has seen every opponent take a turn
+	history := make([]bots.OpponentTurn, 6)
+	for i := range history {
+		history[i] = bots.OpponentTurn{Seat: i + 1, Turn: i + 1, Stack: bots.StackDeck, Discard: game.MustDecodeCard("4-B")}
+	}
+
+	for round := 3; round <= 13; round++ {
+		first := bots.BotRequest{Round: round, PlayerCount: 7, Turn: 1}
+		last := bots.BotRequest{Round: round, PlayerCount: 7, Turn: 7, History: history}
+		late := bots.BotRequest{Round: round, PlayerCount: 7, Turn: 50}
+
+		assert.False(t, DamageControl(first), "round %d first seat", round)
+		assert.False(t, DamageControl(last), "round %d last seat", round)
+		assert.True(t, DamageControl(late), "round %d late", round)
+	}
+}
+
+func TestShed(t *testing.T) {
+
+	cases := []struct {
+		Name      string
+		Sequences []string
+		Round     int
+		Chance    float64
+		Expected  string
+		Ok        bool
+	}{
+		{
+			Name:      "keeps partial sequences",
+			Sequences: []string{"11-R:12-R", "9-B"},
+			Round:     3,
+			Chance:    0.4,
+			Expected:  "9-B",
+			Ok:        true,
+		},
+		{
+			Name:      "sheds high cards",
+			Sequences: []string{"11-R:12-R", "9-B"},
+			Round:     3,
+			Chance:    0.8,
+			Expected:  "12-R",
+			Ok:        true,
+		},
+		{
+			Name:      "sheds unused jokers",
+			Sequences: []string{"3-B:4-B:5-B", "*", "13-R"},
+			Round:     8,
+			Chance:    0.5,
+			Expected:  "*",
+			Ok:        true,
+		},
+		{
+			Name:      "every card is in a sequence",
+			Sequences: []string{"3-B:4-B:5-B", "9-R:9-B:9-Y"},
+			Round:     8,
+			Chance:    1,
+			Ok:        false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			seqs := make([][]game.Card, len(c.Sequences))
+
+			for i, seq := range c.Sequences {
+				cards, err := game.DecodeCards(strings.Split(seq, ":"))
+				assert.NoError(t, err)
+
+				seqs[i] = cards
+			}
+
+			shed, ok := Shed(game.HouseRules, c.Round, seqs, c.Chance)
+
+			assert.Equal(t, c.Ok, ok)
+
+			if c.Ok {
+				assert.Equal(t, c.Expected, shed.Card.Encode())
+				assert.Equal(t, shed.Card, seqs[shed.SequenceIdx][shed.CardIdx])
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package wsbot
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"github.com/timtatt/fivecrowns/bots"
+)
+
+// dials out to the server and answers every request with the given bot until the connection closes
+// this lets bots which are behind NAT take part in matches
+// the secret is the one given when the bot was registered
+func Serve(url string, secret string, bot bots.Bot) error {
+	ws, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Authorization": {"Bearer " + secret}})
+
+	if err != nil {
+		return fmt.Errorf("unable to connect to %s: %w", url, err)
+	}
+
+	defer ws.Close()
+
+	for {
+		var msg Message
+		if err := ws.ReadJSON(&msg); err != nil {
+			return err
+		}
+
+		if msg.Request == nil {
+			continue
+		}
+
+		reply := Message{ID: msg.ID}
+
+		var res any
+		switch msg.Request.Action {
+		case bots.ActionDraw:
+			res, err = bot.Draw(*msg.Request)
+		case bots.ActionDiscard:
+			res, err = bot.Discard(*msg.Request)
+		case bots.ActionScore:
+			res, err = bot.Score(*msg.Request)
+		default:
+			err = fmt.Errorf("unknown action: %s", msg.Request.Action)
+		}
+
+		if err != nil {
+			slog.Error("failed to get bot response", "err", err)
+			reply.Error = err.Error()
+		} else if reply.Response, err = json.Marshal(res); err != nil {
+			reply.Error = err.Error()
+		}
+
+		if err := ws.WriteJSON(reply); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package wsbot
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/timtatt/fivecrowns/bots"
+)
+
+const DefaultTimeout = 5 * time.Second
+
+// Hub keeps track of every bot connected over websocket by name
+
+type Hub struct {
+	upgrader websocket.Upgrader
+	timeout  time.Duration
+
+	// decides whether a bot can connect under a name with the secret it sends as a bearer token
+	// every connection is refused when nil
+	Authorise func(name string, secret string) bool
+
+	mu    sync.Mutex
+	conns map[string]*Conn
+}
+
+func NewHub(timeout time.Duration) *Hub {
+	return &Hub{
+		upgrader: websocket.Upgrader{
+			// bots are not browsers, so any origin is accepted
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		timeout: timeout,
+		conns:   make(map[string]*Conn),
+	}
+}
+
+// upgrades the request and registers the connection under the {name} path value
+// a new connection with the same name replaces the old one, so the name's secret is checked before upgrading
+func (h *Hub) Handler(res http.ResponseWriter, req *http.Request) {
+	name := req.PathValue("name")
+	secret, _ := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+
+	if h.Authorise == nil || !h.Authorise(name, secret) {
+		slog.Warn("refused websocket bot", "bot", name)
+		res.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	ws, err := h.upgrader.Upgrade(res, req, nil)
+
+	if err != nil {
+		slog.Error("unable to upgrade websocket", "bot", name, "err", err)
+		return
+	}
+
+	conn := NewConn(ws, h.timeout)
+
+	h.mu.Lock()
+	if old, ok := h.conns[name]; ok {
+		old.Close()
+	}
+	h.conns[name] = conn
+	h.mu.Unlock()
+
+	slog.Info("bot connected", "bot", name)
+
+	err = conn.Listen()
+
+	h.mu.Lock()
+	if h.conns[name] == conn {
+		delete(h.conns, name)
+	}
+	h.mu.Unlock()
+
+	slog.Info("bot disconnected", "bot", name, "err", err)
+}
+
+// returns the connected bot with the given name
+func (h *Hub) Bot(name string) (bots.Bot, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	conn, ok := h.conns[name]
+
+	return conn, ok
+}
+
+// returns the names of every connected bot
+func (h *Hub) Names() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	names := make([]string, 0, len(h.conns))
+	for name := range h.conns {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// returns a bot which plays through whichever connection has the name at the time of each request
+// so a bot can be registered before it connects, and can reconnect between turns
+func (h *Hub) Remote(name string) bots.Bot {
+	return &remoteBot{
+		hub:  h,
+		name: name,
+	}
+}
+
+type remoteBot struct {
+	hub  *Hub
+	name string
+}
+
+func (b *remoteBot) conn() (bots.Bot, error) {
+	conn, ok := b.hub.Bot(b.name)
+
+	if !ok {
+		return nil, fmt.Errorf("bot is not connected: %s", b.name)
+	}
+
+	return conn, nil
+}
+
+func (b *remoteBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	conn, err := b.conn()
+
+	if err != nil {
+		return bots.DrawResponse{}, err
+	}
+
+	return conn.Draw(req)
+}
+
+func (b *remoteBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	conn, err := b.conn()
+
+	if err != nil {
+		return bots.DiscardResponse{}, err
+	}
+
+	return conn.Discard(req)
+}
+
+func (b *remoteBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	conn, err := b.conn()
+
+	if err != nil {
+		return bots.ScoreResponse{}, err
+	}
+
+	return conn.Score(req)
+}
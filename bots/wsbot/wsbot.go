@@ -0,0 +1,161 @@
+package wsbot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/timtatt/fivecrowns/bots"
+)
+
+// WsBot plays through a bot which holds a persistent websocket connection to the server
+// the bot dials in once and then answers every request for the match over the same connection
+
+// Message is the envelope for every frame sent over the connection
+// requests from the server carry Request, replies from the bot carry Response or Error with the same ID
+type Message struct {
+	ID       int              `json:"id"`
+	Request  *bots.BotRequest `json:"request,omitempty"`
+	Response json.RawMessage  `json:"response,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+var ErrClosed = errors.New("websocket connection is closed")
+
+// Conn is a connected bot. it implements bots.Bot
+type Conn struct {
+	ws      *websocket.Conn
+	timeout time.Duration
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  int
+	pending map[int]chan Message
+	closed  bool
+	done    chan struct{}
+}
+
+func NewConn(ws *websocket.Conn, timeout time.Duration) *Conn {
+	return &Conn{
+		ws:      ws,
+		timeout: timeout,
+		pending: make(map[int]chan Message),
+		done:    make(chan struct{}),
+	}
+}
+
+// reads replies from the bot until the connection closes
+func (c *Conn) Listen() error {
+	defer c.Close()
+
+	for {
+		var msg Message
+		if err := c.ws.ReadJSON(&msg); err != nil {
+			return err
+		}
+
+		c.mu.Lock()
+		ch, ok := c.pending[msg.ID]
+		delete(c.pending, msg.ID)
+		c.mu.Unlock()
+
+		if ok {
+			ch <- msg
+		}
+	}
+}
+
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+
+	c.closed = true
+	close(c.done)
+
+	return c.ws.Close()
+}
+
+// returns a channel which is closed once the connection closes
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *Conn) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	req.Action = bots.ActionDraw
+
+	var res bots.DrawResponse
+	err := c.request(req, &res)
+
+	return res, err
+}
+
+func (c *Conn) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	req.Action = bots.ActionDiscard
+
+	var res bots.DiscardResponse
+	err := c.request(req, &res)
+
+	return res, err
+}
+
+func (c *Conn) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	req.Action = bots.ActionScore
+
+	var res bots.ScoreResponse
+	err := c.request(req, &res)
+
+	return res, err
+}
+
+func (c *Conn) request(req bots.BotRequest, res any) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrClosed
+	}
+
+	c.nextID += 1
+	id := c.nextID
+	ch := make(chan Message, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	c.writeMu.Lock()
+	err := c.ws.WriteJSON(Message{ID: id, Request: &req})
+	c.writeMu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("unable to send request: %w", err)
+	}
+
+	select {
+	case msg := <-ch:
+		if msg.Error != "" {
+			return fmt.Errorf("bot returned an error: %s", msg.Error)
+		}
+
+		if err := json.Unmarshal(msg.Response, res); err != nil {
+			return fmt.Errorf("unable to decode response: %w", err)
+		}
+
+		return nil
+	case <-c.done:
+		return ErrClosed
+	case <-time.After(c.timeout):
+		return fmt.Errorf("bot did not respond within %s", c.timeout)
+	}
+}
@@ -0,0 +1,145 @@
+package wsbot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/engine"
+)
+
+// every bot's secret is its name backwards
+func secret(name string) string {
+	r := []rune(name)
+	slices.Reverse(r)
+
+	return string(r)
+}
+
+func newServer(t *testing.T) (*Hub, string) {
+	hub := NewHub(time.Second)
+	hub.Authorise = func(name string, s string) bool {
+		return s == secret(name)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ws/bots/{name}", hub.Handler)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return hub, "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/bots/"
+}
+
+func connect(t *testing.T, name string, bot bots.Bot) *Hub {
+	hub, url := newServer(t)
+
+	go Serve(url+name, secret(name), bot)
+
+	require.Eventually(t, func() bool {
+		_, ok := hub.Bot(name)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	return hub
+}
+
+func TestWsBot(t *testing.T) {
+
+	hub := connect(t, "grugbot", grugbot.NewGrugBot())
+
+	bot, ok := hub.Bot("grugbot")
+	require.True(t, ok)
+	assert.Equal(t, []string{"grugbot"}, hub.Names())
+
+	res, err := bot.Discard(bots.BotRequest{
+		Hand:  game.MustDecodeSequence("5-X:3-B:5-R:9-Y:13-B:11-X:6-Y"),
+		Round: 7,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, bots.ActionDiscard, res.Action)
+	assert.Equal(t, "13-B", res.Card)
+}
+
+func TestWsBotPlaysMatch(t *testing.T) {
+
+	hub := connect(t, "remote", grugbot.NewGrugBot())
+
+	remote, ok := hub.Bot("remote")
+	require.True(t, ok)
+
+	e := engine.NewEngine([]engine.Player{
+		{Name: "remote", Bot: remote},
+		{Name: "grugbot", Bot: grugbot.NewGrugBot()},
+	}, 1)
+
+	res, err := e.Play()
+
+	require.NoError(t, err)
+	assert.Len(t, res.Rounds, 11)
+}
+
+func TestWsBotClosed(t *testing.T) {
+
+	hub := connect(t, "grugbot", grugbot.NewGrugBot())
+
+	bot, _ := hub.Bot("grugbot")
+	bot.(*Conn).Close()
+
+	_, err := bot.Draw(bots.BotRequest{})
+
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestWsBotRemote(t *testing.T) {
+
+	hub := connect(t, "grugbot", grugbot.NewGrugBot())
+
+	res, err := hub.Remote("grugbot").Draw(bots.BotRequest{
+		Hand:    game.MustDecodeSequence("9-R:10-R:5-X"),
+		Round:   3,
+		Discard: game.MustDecodeSequence("11-R"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, bots.ActionDraw, res.Action)
+
+	// a bot which has not connected yet
+	_, err = hub.Remote("bigbrainbot").Draw(bots.BotRequest{})
+	assert.Error(t, err)
+}
+
+func TestWsBotSecret(t *testing.T) {
+
+	hub, url := newServer(t)
+
+	go Serve(url+"grugbot", secret("grugbot"), grugbot.NewGrugBot())
+
+	require.Eventually(t, func() bool {
+		_, ok := hub.Bot("grugbot")
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	bot, _ := hub.Bot("grugbot")
+
+	// another bot cannot take over the name without its secret
+	assert.Error(t, Serve(url+"grugbot", "wrong", grugbot.NewGrugBot()))
+	assert.Error(t, Serve(url+"grugbot", "", grugbot.NewGrugBot()))
+
+	again, ok := hub.Bot("grugbot")
+	require.True(t, ok)
+	assert.Same(t, bot, again)
+
+	// without an Authorise func nobody can connect
+	hub.Authorise = nil
+	assert.Error(t, Serve(url+"bigbrainbot", secret("bigbrainbot"), grugbot.NewGrugBot()))
+}
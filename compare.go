@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/timtatt/fivecrowns/batch"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/tournament"
+)
+
+// plays two bots on the same deals until the difference between them is clear
+func compare(args []string) error {
+
+	defaults := batch.DefaultCompareConfig
+
+	flags := flag.NewFlagSet("compare", flag.ExitOnError)
+	a := flags.String("a", "grugbot", "specify the first bot. built in bots by name, remote http bots by url")
+	b := flags.String("b", "bigbrainbot", "specify the second bot. built in bots by name, remote http bots by url")
+	opponentNames := flags.String("opponents", "", "specify the comma separated bots both bots play against. when empty the bots play each other")
+	pairs := flags.Int("pairs", defaults.MaxPairs, "specify the most pairs of games to play")
+	step := flags.Int("step", defaults.Step, "specify the pairs to play between each look at the results")
+	seed := flags.Uint64("seed", defaults.Seed, "specify the seed of the first pair")
+	alpha := flags.Float64("alpha", defaults.Alpha, "specify the chance of calling a difference when there is none")
+	earlyAlpha := flags.Float64("early-alpha", defaults.EarlyAlpha, "specify the stricter threshold for stopping before the last look")
+	margin := flags.Float64("margin", defaults.Margin, "specify the difference in penalty per round too small to matter. 0 to only stop on a difference")
+	concurrency := flags.Int("concurrency", 0, "specify the number of games to play at the same time. defaults to the number of CPUs")
+	rulesName := flags.String("rules", game.HouseRules.Name, "specify the rules the games are played with: house, official")
+	asJSON := flags.Bool("json", false, "print the comparison as json")
+	flags.Parse(args)
+
+	bots, err := entrants([]string{*a, *b})
+
+	if err != nil {
+		return err
+	}
+
+	var opponents []tournament.Entrant
+
+	if *opponentNames != "" {
+		opponents, err = entrants(strings.Split(*opponentNames, ","))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	rules, err := game.LookupRules(*rulesName)
+
+	if err != nil {
+		return err
+	}
+
+	cmp, err := batch.Compare(batch.CompareConfig{
+		A:           bots[0],
+		B:           bots[1],
+		Opponents:   opponents,
+		Seed:        *seed,
+		MaxPairs:    *pairs,
+		Step:        *step,
+		Alpha:       *alpha,
+		EarlyAlpha:  *earlyAlpha,
+		Margin:      *margin,
+		Concurrency: *concurrency,
+		Rules:       &rules,
+	})
+
+	if cmp.Pairs == 0 {
+		return fmt.Errorf("unable to compare bots: %w", err)
+	} else if err != nil {
+		slog.Warn("some pairs failed", "failed", cmp.Failed, "err", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(cmp)
+	}
+
+	return batch.WriteComparison(os.Stdout, cmp)
+}
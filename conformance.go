@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/timtatt/fivecrowns/conformance"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+// runs the conformance suite against a built in bot or a bot at a url and prints the report
+func conform(args []string) error {
+
+	flags := flag.NewFlagSet("conformance", flag.ExitOnError)
+	botName := flags.String("bot", "", "specify a built in bot to check")
+	url := flags.String("url", "", "specify the url of a remote http bot to check e.g. http://localhost:8080/bots/mybot")
+	hands := flags.Int("hands", conformance.DefaultConfig.Hands, "specify the number of hands dealt for each round")
+	deadline := flags.Duration("deadline", conformance.DefaultConfig.Deadline, "specify the longest the bot may take to answer a request")
+	seed := flags.Uint64("seed", conformance.DefaultConfig.Seed, "specify the seed used to deal the hands")
+	rulesName := flags.String("rules", "", "specify the rules sent with every request: house, official. by default no rules are sent")
+	flags.Parse(args)
+
+	config := conformance.Config{
+		Hands:    *hands,
+		Deadline: *deadline,
+		Seed:     *seed,
+	}
+
+	if *rulesName != "" {
+		rules, err := game.LookupRules(*rulesName)
+
+		if err != nil {
+			return err
+		}
+
+		config.Rules = &rules
+	}
+
+	var report conformance.Report
+
+	switch {
+	case *botName != "" && *url != "":
+		return errors.New("specify either -bot or -url, not both")
+	case *botName != "":
+		newBot, ok := botFactories[*botName]
+
+		if !ok {
+			return fmt.Errorf("unknown bot: %s", *botName)
+		}
+
+		report = conformance.Run(*botName, newBot(), config)
+	case *url != "":
+		report = conformance.RunURL(*url, config)
+	default:
+		return errors.New("specify a bot with -bot or -url")
+	}
+
+	if err := conformance.WriteReport(os.Stdout, report); err != nil {
+		return err
+	}
+
+	if !report.Passed() {
+		return fmt.Errorf("%s failed %d checks", report.Bot, report.Failed())
+	}
+
+	return nil
+}
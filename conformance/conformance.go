@@ -0,0 +1,395 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/referee"
+)
+
+// Conformance sends a bot requests covering the whole protocol and reports every check it fails
+// bots should pass every check before they join a tournament
+
+type Config struct {
+	// hands dealt for each round
+	Hands int
+	// longest the bot may take to answer a single request
+	Deadline time.Duration
+	Seed     uint64
+	// when set, every request carries the rules. bots are dealt from the rules' deck over its rounds
+	Rules *game.RuleSet
+}
+
+var DefaultConfig = Config{
+	Hands:    3,
+	Deadline: 5 * time.Second,
+	Seed:     1,
+}
+
+var ErrDeadline = errors.New("no response before the deadline")
+
+type Check struct {
+	Name        string
+	Description string
+}
+
+var (
+	CheckAction       = Check{"action", "every response has the action of its request"}
+	CheckRounds       = Check{"rounds", "every request in rounds 3 to 13 is answered"}
+	CheckEmptyDiscard = Check{"empty discard", "draws from the deck when the discard pile is empty"}
+	CheckHand         = Check{"discards from hand", "only discards cards in the hand"}
+	CheckRules        = Check{"rules", "uses every remaining card once and only flops with valid sequences"}
+	CheckJSON         = Check{"json", "every response is a valid json object for its action"}
+	CheckDeadline     = Check{"deadline", "every response arrives before the deadline"}
+)
+
+// the order checks are reported in
+var Checks = []Check{CheckAction, CheckRounds, CheckEmptyDiscard, CheckHand, CheckRules, CheckJSON, CheckDeadline}
+
+type Result struct {
+	Check
+	// requests the check was applied to
+	Requests int
+	Failures int
+	// the first failure
+	Detail string
+}
+
+func (r Result) Passed() bool {
+	return r.Failures == 0
+}
+
+type Report struct {
+	Bot     string
+	Results []Result
+	// longest time taken to answer a request
+	Slowest time.Duration
+}
+
+// returns the number of checks the bot failed
+func (r Report) Failed() int {
+	failed := 0
+	for _, res := range r.Results {
+		if !res.Passed() {
+			failed += 1
+		}
+	}
+
+	return failed
+}
+
+func (r Report) Passed() bool {
+	return r.Failed() == 0
+}
+
+// sends a request and returns the raw json response
+type transport func(req bots.BotRequest) ([]byte, error)
+
+// checks a bot in the same process. panics are reported as failures
+func Run(name string, bot bots.Bot, config Config) Report {
+	return run(name, local(bot), config)
+}
+
+// checks a bot which implements the HTTP spec in the README at the url
+func RunURL(url string, config Config) Report {
+	return run(url, remote(url, &http.Client{Timeout: config.Deadline}), config)
+}
+
+func local(bot bots.Bot) transport {
+	return func(req bots.BotRequest) (raw []byte, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("bot panicked: %v", r)
+			}
+		}()
+
+		var res any
+
+		switch req.Action {
+		case bots.ActionDraw:
+			res, err = bot.Draw(req)
+		case bots.ActionDiscard:
+			res, err = bot.Discard(req)
+		case bots.ActionScore:
+			res, err = bot.Score(req)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(res)
+	}
+}
+
+func remote(url string, client *http.Client) transport {
+	return func(req bots.BotRequest) ([]byte, error) {
+		body, err := json.Marshal(req)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode request: %w", err)
+		}
+
+		res, err := client.Post(url, "application/json", bytes.NewReader(body))
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer res.Body.Close()
+
+		raw, err := io.ReadAll(res.Body)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to read response: %w", err)
+		} else if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status: %s", res.Status)
+		}
+
+		return raw, nil
+	}
+}
+
+// gives up waiting for a response after the deadline, so a bot which hangs does not hang the suite
+func withDeadline(deadline time.Duration, send transport) transport {
+	type response struct {
+		raw []byte
+		err error
+	}
+
+	return func(req bots.BotRequest) ([]byte, error) {
+		done := make(chan response, 1)
+
+		go func() {
+			raw, err := send(req)
+			done <- response{raw, err}
+		}()
+
+		select {
+		case res := <-done:
+			return res.raw, res.err
+		case <-time.After(deadline):
+			return nil, ErrDeadline
+		}
+	}
+}
+
+type suite struct {
+	send    transport
+	config  Config
+	results map[string]*Result
+	slowest time.Duration
+}
+
+func run(name string, send transport, config Config) Report {
+	s := &suite{
+		send:    withDeadline(config.Deadline, send),
+		config:  config,
+		results: make(map[string]*Result, len(Checks)),
+	}
+
+	for _, check := range Checks {
+		s.results[check.Name] = &Result{Check: check}
+	}
+
+	r := rand.New(rand.NewPCG(config.Seed, 0))
+
+	rules := game.HouseRules
+	if config.Rules != nil {
+		rules = *config.Rules
+	}
+
+	for round := rules.FirstRound; round <= rules.LastRound; round++ {
+		for range max(config.Hands, 1) {
+			deck := rules.NewDeck()
+			deck.Shuffle(r)
+
+			cards := deck.Cards()
+			hand, pile := cards[:round+1], cards[round+1:round+1+r.IntN(3)+1]
+
+			req := bots.BotRequest{
+				Hand:        slices.Clone(hand[:round]),
+				Discard:     pile,
+				Round:       round,
+				PlayerCount: 2,
+				Turn:        1,
+				DeckCount:   len(cards) - len(hand) - len(pile),
+				Rules:       config.Rules,
+			}
+
+			s.draw(CheckRounds, req)
+			s.score(req)
+
+			// the bot has drawn a card
+			req.Hand = slices.Clone(hand)
+			s.discard(CheckRounds, req)
+		}
+
+		// the discard pile is empty at the start of a round and when the only card is picked up
+		deck := rules.NewDeck()
+		deck.Shuffle(r)
+
+		cards := deck.Cards()
+
+		req := bots.BotRequest{
+			Hand:        slices.Clone(cards[:round]),
+			Discard:     []game.Card{},
+			Round:       round,
+			PlayerCount: 2,
+			Turn:        1,
+			DeckCount:   len(cards) - round,
+			Rules:       config.Rules,
+		}
+
+		s.draw(CheckEmptyDiscard, req)
+
+		req.Hand = slices.Clone(cards[:round+1])
+		s.discard(CheckEmptyDiscard, req)
+	}
+
+	report := Report{
+		Bot:     name,
+		Slowest: s.slowest,
+	}
+
+	for _, check := range Checks {
+		report.Results = append(report.Results, *s.results[check.Name])
+	}
+
+	return report
+}
+
+func (s *suite) check(check Check, failed bool, detail func() string) {
+	res := s.results[check.Name]
+	res.Requests += 1
+
+	if failed {
+		if res.Failures == 0 {
+			res.Detail = detail()
+		}
+
+		res.Failures += 1
+	}
+}
+
+// sends the request and decodes the response, checking the deadline, json and action
+// errors are reported against the scope, the check the request was made for
+func (s *suite) request(scope Check, req bots.BotRequest, res any, action func() bots.Action) bool {
+	describe := func(detail string, args ...any) func() string {
+		return func() string {
+			return fmt.Sprintf("round %d %s: %s", req.Round, req.Action, fmt.Sprintf(detail, args...))
+		}
+	}
+
+	start := time.Now()
+	raw, err := s.send(req)
+	elapsed := time.Since(start)
+
+	s.slowest = max(s.slowest, elapsed)
+
+	s.check(CheckDeadline, errors.Is(err, ErrDeadline), describe("took longer than %s", s.config.Deadline))
+	s.check(scope, err != nil && !errors.Is(err, ErrDeadline), describe("%v", err))
+
+	if err != nil {
+		return false
+	}
+
+	err = json.Unmarshal(raw, res)
+	s.check(CheckJSON, err != nil, describe("%v: %.100s", err, raw))
+
+	if err != nil {
+		return false
+	}
+
+	s.check(CheckAction, action() != req.Action, describe("expected action %q but got %q", req.Action, action()))
+
+	return true
+}
+
+func (s *suite) draw(scope Check, req bots.BotRequest) {
+	req.Action = bots.ActionDraw
+
+	var res bots.DrawResponse
+	if !s.request(scope, req, &res, func() bots.Action { return res.Action }) {
+		return
+	}
+
+	if len(req.Discard) == 0 {
+		s.check(CheckEmptyDiscard, res.Stack != bots.StackDeck, func() string {
+			return fmt.Sprintf("round %d draw: drew from the %q stack", req.Round, res.Stack)
+		})
+
+		return
+	}
+
+	s.rules(req, referee.CheckDraw(req, res))
+}
+
+func (s *suite) discard(scope Check, req bots.BotRequest) {
+	req.Action = bots.ActionDiscard
+
+	var res bots.DiscardResponse
+	if !s.request(scope, req, &res, func() bots.Action { return res.Action }) {
+		return
+	}
+
+	s.check(CheckHand, !slices.Contains(game.EncodeCards(req.Hand), res.Card), func() string {
+		return fmt.Sprintf("round %d discard: discarded %q which is not in the hand %v", req.Round, res.Card, req.Hand)
+	})
+
+	s.rules(req, referee.CheckDiscard(req, res))
+}
+
+func (s *suite) score(req bots.BotRequest) {
+	req.Action = bots.ActionScore
+
+	var res bots.ScoreResponse
+	if !s.request(CheckRounds, req, &res, func() bots.Action { return res.Action }) {
+		return
+	}
+
+	s.rules(req, referee.CheckScore(req, res))
+}
+
+// the action has its own check, so wrong actions are left out
+func (s *suite) rules(req bots.BotRequest, violations []referee.Violation) {
+	violations = slices.DeleteFunc(violations, func(v referee.Violation) bool {
+		return v.Kind == referee.KindWrongAction
+	})
+
+	s.check(CheckRules, len(violations) > 0, func() string {
+		return fmt.Sprintf("round %d %s: %s", req.Round, req.Action, violations[0])
+	})
+}
+
+func WriteReport(w io.Writer, report Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "conformance report for %s\n\n", report.Bot)
+	fmt.Fprintln(tw, "result\tcheck\trequests\tfailures\tdetail")
+
+	for _, res := range report.Results {
+		result, detail := "PASS", res.Description
+
+		if !res.Passed() {
+			result, detail = "FAIL", res.Detail
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n", result, res.Name, res.Requests, res.Failures, detail)
+	}
+
+	fmt.Fprintf(tw, "\nslowest response: %s\n", report.Slowest.Round(time.Millisecond))
+	fmt.Fprintf(tw, "passed %d of %d checks\n", len(report.Results)-report.Failed(), len(report.Results))
+
+	return tw.Flush()
+}
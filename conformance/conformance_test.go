@@ -0,0 +1,139 @@
+package conformance
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/bigbrainbot"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/bots/httpbot"
+	"github.com/timtatt/fivecrowns/bots/smoothbrainbot"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+var config = Config{
+	Hands:    1,
+	Deadline: time.Second,
+	Seed:     1,
+}
+
+func TestBuiltInBots(t *testing.T) {
+
+	cases := map[string]func() bots.Bot{
+		"smoothbrainbot": smoothbrainbot.NewSmoothBrainBot,
+		"grugbot":        grugbot.NewGrugBot,
+		"bigbrainbot":    bigbrainbot.NewBigBrainBot,
+	}
+
+	for name, newBot := range cases {
+		t.Run(name, func(t *testing.T) {
+			report := Run(name, newBot(), config)
+
+			for _, res := range report.Results {
+				assert.True(t, res.Passed(), "%s: %s", res.Name, res.Detail)
+				assert.Positive(t, res.Requests, res.Name)
+			}
+		})
+	}
+}
+
+func TestRules(t *testing.T) {
+
+	rules := game.OfficialRules
+	rules.MinSequenceLength = 4
+
+	report := Run("bigbrainbot", bigbrainbot.NewBigBrainBot(), Config{Hands: 1, Deadline: time.Second, Seed: 1, Rules: &rules})
+
+	assert.True(t, report.Passed())
+}
+
+// answers every request with the same response
+type brokenBot struct {
+	draw    bots.DrawResponse
+	discard bots.DiscardResponse
+	delay   time.Duration
+}
+
+func (b *brokenBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	time.Sleep(b.delay)
+	return b.draw, nil
+}
+
+func (b *brokenBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	if len(req.Discard) == 0 {
+		panic("no discard pile")
+	}
+
+	return b.discard, nil
+}
+
+func (b *brokenBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	return bots.ScoreResponse{Action: bots.ActionScore, Sequences: [][]string{game.EncodeCards(req.Hand)}}, nil
+}
+
+func TestBrokenBot(t *testing.T) {
+
+	bot := &brokenBot{
+		draw:    bots.DrawResponse{Action: bots.ActionDraw, Stack: bots.StackDiscard},
+		discard: bots.DiscardResponse{Action: bots.ActionScore, Card: "3-R"},
+		delay:   time.Millisecond,
+	}
+
+	report := Run("broken", bot, Config{Hands: 1, Deadline: 100 * time.Millisecond, Seed: 1})
+
+	failed := make([]string, 0)
+	for _, res := range report.Results {
+		if !res.Passed() {
+			failed = append(failed, res.Name)
+		}
+	}
+
+	assert.Equal(t, []string{"action", "empty discard", "discards from hand", "rules"}, failed)
+	assert.False(t, report.Passed())
+
+	var out bytes.Buffer
+	require.NoError(t, WriteReport(&out, report))
+	assert.Contains(t, out.String(), "FAIL")
+	assert.Contains(t, out.String(), "passed 3 of 7 checks")
+}
+
+func TestDeadline(t *testing.T) {
+
+	bot := &brokenBot{
+		draw:  bots.DrawResponse{Action: bots.ActionDraw, Stack: bots.StackDeck},
+		delay: 50 * time.Millisecond,
+	}
+
+	report := Run("slow", bot, Config{Hands: 1, Deadline: 10 * time.Millisecond, Seed: 1})
+
+	assert.False(t, report.Results[len(report.Results)-1].Passed())
+	assert.Equal(t, CheckDeadline, report.Results[len(report.Results)-1].Check)
+}
+
+func TestRemote(t *testing.T) {
+
+	server := httptest.NewServer(httpbot.NewHandler("grugbot", grugbot.NewGrugBot()))
+	defer server.Close()
+
+	report := RunURL(server.URL, config)
+	assert.True(t, report.Passed())
+
+	invalid := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(`{"action": "draw", "stack": `))
+	}))
+	defer invalid.Close()
+
+	report = RunURL(invalid.URL, config)
+
+	for _, res := range report.Results {
+		if res.Check == CheckJSON {
+			assert.False(t, res.Passed())
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/evaluation"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+// asks a built in bot for its answer to a single hand e.g. `go run . eval -hand 5-B:*:5-R:4-B:6-B`
+func eval(args []string) error {
+
+	flags := flag.NewFlagSet("eval", flag.ExitOnError)
+	botName := flags.String("bot", "bigbrainbot", "specify the built in bot to ask")
+	hand := flags.String("hand", "", "specify the hand as a sequence code e.g. 5-B:*:5-R:4-B:6-B. when discarding, include the drawn card")
+	round := flags.Int("round", 0, "specify the round. by default it is worked out from the size of the hand")
+	discard := flags.String("discard", "", "specify the discard pile as a sequence code, top-most card first")
+	action := flags.String("action", string(bots.ActionDiscard), "specify the action the bot performs: draw, discard, score")
+	players := flags.Int("players", 2, "specify the number of players at the table")
+	lastTurn := flags.Bool("last-turn", false, "specify that it is the bot's last turn of the round")
+	rulesName := flags.String("rules", "", "specify the rules sent with the request: house, official. by default no rules are sent")
+	asJSON := flags.Bool("json", false, "print the result as json")
+	flags.Parse(args)
+
+	if *hand == "" {
+		return errors.New("specify a hand with -hand")
+	}
+
+	newBot, ok := botFactories[*botName]
+
+	if !ok {
+		return fmt.Errorf("unknown bot: %s", *botName)
+	}
+
+	h := evaluation.Hand{
+		Action:      bots.Action(*action),
+		Cards:       *hand,
+		Round:       *round,
+		Discard:     *discard,
+		PlayerCount: *players,
+		LastTurn:    *lastTurn,
+	}
+
+	if *rulesName != "" {
+		rules, err := game.LookupRules(*rulesName)
+
+		if err != nil {
+			return err
+		}
+
+		h.Rules = &rules
+	}
+
+	res, err := evaluation.Evaluate(*botName, newBot(), h)
+
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(res)
+	}
+
+	return evaluation.WriteResult(os.Stdout, res)
+}
@@ -0,0 +1,253 @@
+package evaluation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/referee"
+)
+
+// Evaluation asks a bot for its answer to a single hand and scores it
+// it is the command line version of the arena page, handy for working out the expected answers in bot tests
+
+type Hand struct {
+	Action bots.Action
+	// the cards in the hand as a sequence code e.g. 5-B:*:5-R:4-B:6-B
+	// when discarding, the hand includes the card which has just been drawn
+	Cards string
+	// the round being played. zero works it out from the number of cards
+	Round int
+	// the discard pile as a sequence code, top-most card first
+	Discard     string
+	PlayerCount int
+	LastTurn    bool
+	// the rules sent with the request, nil sends none and plays the house rules
+	Rules *game.RuleSet
+}
+
+type Result struct {
+	Bot    string      `json:"bot"`
+	Action bots.Action `json:"action"`
+	Round  int         `json:"round"`
+	Hand   []game.Card `json:"hand"`
+	// the stack chosen when drawing
+	Stack bots.Stack `json:"stack,omitempty"`
+	// the card chosen when discarding
+	Discard   string     `json:"discard,omitempty"`
+	Sequences [][]string `json:"sequences,omitempty"`
+	Flop      bool       `json:"flop"`
+	// points left in the hand arranged into the bot's sequences
+	Penalty int `json:"penalty"`
+	// the lowest penalty any answer could have left
+	Optimal int `json:"optimal"`
+	// the ways the answer breaks the rules
+	Violations []string `json:"violations,omitempty"`
+}
+
+// builds the request for the hand
+func (h Hand) Request() (bots.BotRequest, error) {
+	action := h.Action
+	if action == "" {
+		action = bots.ActionDiscard
+	}
+
+	cards, err := game.DecodeSequence(h.Cards)
+
+	if err != nil {
+		return bots.BotRequest{}, fmt.Errorf("unable to decode hand: %w", err)
+	} else if len(cards) == 0 {
+		return bots.BotRequest{}, errors.New("hand has no cards")
+	}
+
+	discard := make([]game.Card, 0)
+
+	if h.Discard != "" {
+		discard, err = game.DecodeSequence(h.Discard)
+
+		if err != nil {
+			return bots.BotRequest{}, fmt.Errorf("unable to decode discard pile: %w", err)
+		}
+	}
+
+	round := h.Round
+
+	// the same as the arena, a hand being discarded from has an extra card
+	if round == 0 {
+		round = len(cards)
+
+		if action == bots.ActionDiscard {
+			round -= 1
+		}
+	}
+
+	req := bots.BotRequest{
+		Action:      action,
+		Hand:        cards,
+		Discard:     discard,
+		PlayerCount: max(h.PlayerCount, 2),
+		Round:       round,
+		LastTurn:    h.LastTurn,
+		Turn:        1,
+		Rules:       h.Rules,
+	}
+
+	if err := req.RuleSet().Validate(); err != nil {
+		return bots.BotRequest{}, fmt.Errorf("invalid rules: %w", err)
+	} else if round < req.RuleSet().FirstRound || round > req.RuleSet().LastRound {
+		return bots.BotRequest{}, fmt.Errorf("round %d is not played, must be between %d and %d", round, req.RuleSet().FirstRound, req.RuleSet().LastRound)
+	}
+
+	return req, nil
+}
+
+// sends the hand to the bot and scores its answer
+func Evaluate(name string, bot bots.Bot, hand Hand) (Result, error) {
+	req, err := hand.Request()
+
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{
+		Bot:    name,
+		Action: req.Action,
+		Round:  req.Round,
+		Hand:   req.Hand,
+	}
+
+	var violations []referee.Violation
+
+	switch req.Action {
+	case bots.ActionDraw:
+		draw, err := bot.Draw(req)
+
+		if err != nil {
+			return Result{}, fmt.Errorf("%s failed to draw: %w", name, err)
+		}
+
+		res.Stack = draw.Stack
+		violations = referee.CheckDraw(req, draw)
+	case bots.ActionDiscard:
+		discard, err := bot.Discard(req)
+
+		if err != nil {
+			return Result{}, fmt.Errorf("%s failed to discard: %w", name, err)
+		}
+
+		res.Discard = discard.Card
+		res.Sequences = discard.Sequences
+		res.Flop = discard.Flop
+		violations = referee.CheckDiscard(req, discard)
+	case bots.ActionScore:
+		score, err := bot.Score(req)
+
+		if err != nil {
+			return Result{}, fmt.Errorf("%s failed to score: %w", name, err)
+		}
+
+		res.Sequences = score.Sequences
+		res.Flop = score.Flop
+		violations = referee.CheckScore(req, score)
+	default:
+		return Result{}, fmt.Errorf("unknown action: %s", req.Action)
+	}
+
+	for _, v := range violations {
+		res.Violations = append(res.Violations, v.String())
+	}
+
+	rules := req.RuleSet()
+
+	if req.Action != bots.ActionDraw {
+		// sequences with cards which cannot be decoded are already reported as violations
+		if seqs, err := decodeSequences(res.Sequences); err == nil {
+			res.Penalty = rules.ScorePenalty(seqs, req.Round)
+		}
+
+		res.Optimal = optimal(rules, req.Action, req.Hand, req.Round)
+	}
+
+	return res, nil
+}
+
+// the lowest penalty of the hand, after the best discard when discarding
+func optimal(rules game.RuleSet, action bots.Action, cards []game.Card, round int) int {
+	if action != bots.ActionDiscard {
+		return rules.OptimalArrangement(cards, round).Penalty
+	}
+
+	best := -1
+
+	for i := range cards {
+		rest := slices.Delete(slices.Clone(cards), i, i+1)
+
+		if penalty := rules.OptimalArrangement(rest, round).Penalty; best == -1 || penalty < best {
+			best = penalty
+		}
+	}
+
+	return best
+}
+
+func decodeSequences(seqs [][]string) ([][]game.Card, error) {
+	out := make([][]game.Card, len(seqs))
+
+	for i, seq := range seqs {
+		cards, err := game.DecodeCards(seq)
+
+		if err != nil {
+			return nil, err
+		}
+
+		out[i] = cards
+	}
+
+	return out, nil
+}
+
+func WriteResult(w io.Writer, res Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "bot\t%s\n", res.Bot)
+	fmt.Fprintf(tw, "action\t%s\n", res.Action)
+	fmt.Fprintf(tw, "round\t%d\n", res.Round)
+	fmt.Fprintf(tw, "hand\t%s\n", game.EncodeSequence(res.Hand))
+
+	if res.Action == bots.ActionDraw {
+		fmt.Fprintf(tw, "stack\t%s\n", res.Stack)
+	} else {
+		if res.Action == bots.ActionDiscard {
+			fmt.Fprintf(tw, "discard\t%s\n", res.Discard)
+		}
+
+		for i, seq := range res.Sequences {
+			label := ""
+			if i == 0 {
+				label = "sequences"
+			}
+
+			fmt.Fprintf(tw, "%s\t%s\n", label, strings.Join(seq, ":"))
+		}
+
+		fmt.Fprintf(tw, "flop\t%t\n", res.Flop)
+		fmt.Fprintf(tw, "penalty\t%d\n", res.Penalty)
+		fmt.Fprintf(tw, "optimal\t%d\n", res.Optimal)
+	}
+
+	for i, v := range res.Violations {
+		label := ""
+		if i == 0 {
+			label = "violations"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\n", label, v)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,116 @@
+package evaluation
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/bigbrainbot"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+func TestEvaluate(t *testing.T) {
+
+	official := game.OfficialRules
+
+	cases := []struct {
+		Name    string
+		Hand    Hand
+		Round   int
+		Stack   bots.Stack
+		Discard string
+		Flop    bool
+		Penalty int
+	}{
+		{
+			Name:    "discard",
+			Hand:    Hand{Cards: "5-B:*:5-R:4-B:6-B"},
+			Round:   4,
+			Discard: "5-R",
+			Flop:    true,
+		},
+		{
+			Name:    "score",
+			Hand:    Hand{Action: bots.ActionScore, Cards: "3-R:9-B:13-G"},
+			Round:   3,
+			Penalty: 25,
+		},
+		{
+			Name:  "draw",
+			Hand:  Hand{Action: bots.ActionDraw, Cards: "9-R:10-R:13-G", Discard: "11-R"},
+			Round: 3,
+			Stack: bots.StackDiscard,
+		},
+		{
+			Name:    "official rules",
+			Hand:    Hand{Action: bots.ActionScore, Cards: "*:4-B:8-G", Rules: &official},
+			Round:   3,
+			Penalty: 62,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			res, err := Evaluate("bigbrainbot", bigbrainbot.NewBigBrainBot(), tc.Hand)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.Round, res.Round)
+			assert.Equal(t, tc.Stack, res.Stack)
+			assert.Equal(t, tc.Discard, res.Discard)
+			assert.Equal(t, tc.Flop, res.Flop)
+			assert.Equal(t, tc.Penalty, res.Penalty)
+			assert.Equal(t, tc.Penalty, res.Optimal)
+			assert.Empty(t, res.Violations)
+		})
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+
+	cases := map[string]Hand{
+		"no cards":     {},
+		"invalid card": {Cards: "5-B:99-Z"},
+		"invalid pile": {Cards: "5-B:6-B:7-B", Discard: "x"},
+		"round":        {Cards: "5-B:6-B:7-B", Round: 14},
+		"action":       {Action: "flop", Cards: "5-B:6-B:7-B"},
+	}
+
+	for name, hand := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Evaluate("bigbrainbot", bigbrainbot.NewBigBrainBot(), hand)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// keeps every card and flops regardless
+type cheatBot struct{}
+
+func (cheatBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	return bots.DrawResponse{Action: bots.ActionDraw, Stack: bots.StackDeck}, nil
+}
+
+func (cheatBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	return bots.DiscardResponse{Action: bots.ActionDiscard, Card: req.Hand[0].Encode(), Flop: true, Sequences: [][]string{game.EncodeCards(req.Hand)}}, nil
+}
+
+func (cheatBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	return bots.ScoreResponse{Action: bots.ActionScore, Sequences: [][]string{game.EncodeCards(req.Hand)}}, nil
+}
+
+func TestEvaluateViolations(t *testing.T) {
+
+	res, err := Evaluate("cheatbot", cheatBot{}, Hand{Cards: "3-R:9-B:13-G:11-Y"})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, res.Violations)
+	assert.Equal(t, 36, res.Penalty)
+	assert.Equal(t, 23, res.Optimal)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteResult(&out, res))
+	assert.Contains(t, out.String(), "violations")
+	assert.Contains(t, out.String(), "penalty")
+}
@@ -0,0 +1,63 @@
+package game
+
+import (
+	"errors"
+	"math/rand/v2"
+)
+
+const (
+	// number of copies of each suited card in a deck
+	DeckCopies = 2
+	// number of jokers in a deck
+	DeckJokers = 6
+)
+
+// Deck is an ordered stack of cards. the top of the deck is the last card
+type Deck struct {
+	cards []Card
+}
+
+// creates a full, unshuffled five crowns deck of 116 cards
+func NewDeck() *Deck {
+	return HouseRules.NewDeck()
+}
+
+// creates the random source used to shuffle a round of a match
+// each round has its own stream so any round can be reproduced without playing the rounds before it
+func RoundRand(seed uint64, round int) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, uint64(round)))
+}
+
+// creates a deck from an existing list of cards
+func NewDeckFromCards(cards []Card) *Deck {
+	return &Deck{cards: cards}
+}
+
+// shuffles the deck in place using a Fisher-Yates shuffle
+func (d *Deck) Shuffle(r *rand.Rand) {
+	r.Shuffle(len(d.cards), func(i, j int) {
+		d.cards[i], d.cards[j] = d.cards[j], d.cards[i]
+	})
+}
+
+// returns the number of cards left in the deck
+func (d *Deck) Len() int {
+	return len(d.cards)
+}
+
+// takes the top card off the deck
+func (d *Deck) Draw() (Card, error) {
+	if len(d.cards) == 0 {
+		return Card{}, errors.New("deck is empty")
+	}
+
+	card := d.cards[len(d.cards)-1]
+	d.cards = d.cards[:len(d.cards)-1]
+
+	return card, nil
+}
+
+// returns the cards remaining in the deck, top-most card last
+func (d *Deck) Cards() []Card {
+	return d.cards
+}
@@ -0,0 +1,53 @@
+package game
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDeck(t *testing.T) {
+
+	deck := NewDeck()
+
+	assert.Equal(t, 116, deck.Len())
+
+	counts := make(map[Card]int)
+	for _, card := range deck.Cards() {
+		counts[card] += 1
+	}
+
+	assert.Equal(t, 6, counts[CardJoker])
+	assert.Equal(t, 2, counts[Card{Number: 13, Suite: SuiteGreen}])
+}
+
+func TestDeckDraw(t *testing.T) {
+
+	deck := NewDeck()
+	deck.Shuffle(rand.New(rand.NewPCG(1, 2)))
+
+	for range 116 {
+		_, err := deck.Draw()
+		require.NoError(t, err)
+	}
+
+	_, err := deck.Draw()
+	assert.Error(t, err)
+}
+
+func TestRoundRand(t *testing.T) {
+
+	a := NewDeck()
+	a.Shuffle(RoundRand(10, 11))
+
+	b := NewDeck()
+	b.Shuffle(RoundRand(10, 11))
+
+	c := NewDeck()
+	c.Shuffle(RoundRand(10, 12))
+
+	assert.Equal(t, a.Cards(), b.Cards())
+	assert.NotEqual(t, a.Cards(), c.Cards())
+}
@@ -1,6 +1,7 @@
 package game
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -8,13 +9,19 @@ import (
 	"unicode/utf8"
 )
 
-// encode the card into a string eg. "*" or "10-R"
+// encode the card into a string eg. "*" or "10-R". the nil card encodes as ""
 func (c Card) Encode() string {
 	if c.Joker {
 		return "*"
+	} else if c.IsNil() {
+		return ""
 	}
 
-	return fmt.Sprintf("%d-%c", c.Number, c.Suite)
+	return strconv.Itoa(c.Number) + "-" + c.Suite.String()
+}
+
+func (c Card) String() string {
+	return c.Encode()
 }
 
 // decode the card into a struct
@@ -23,29 +30,59 @@ func DecodeCard(c string) (Card, error) {
 		return CardJoker, nil
 	}
 
-	parts := strings.SplitN(string(c), "-", 2)
-	number, err := strconv.Atoi(parts[0])
+	number, suite, found := strings.Cut(c, "-")
+
+	if !found {
+		return Card{}, fmt.Errorf("invalid card encoding: %s", c)
+	}
+
+	n, err := strconv.Atoi(number)
 
 	if err != nil {
 		return Card{}, fmt.Errorf("unable to decode number: %s", c)
-	} else if number < 3 || number > 13 {
-		return Card{}, fmt.Errorf("invalid number in card encoding: %s", c)
 	}
 
-	suite, _ := utf8.DecodeLastRuneInString(parts[1])
+	r, size := utf8.DecodeRuneInString(suite)
 
-	switch suite {
-	case SuiteBlue, SuiteRed, SuiteBlack, SuiteGreen, SuiteYellow:
-		break
-	default:
+	if size != len(suite) {
 		return Card{}, fmt.Errorf("invalid suite in card encoding: %s", c)
 	}
 
-	return Card{
-		Joker:  false,
-		Number: number,
-		Suite:  suite,
-	}, nil
+	card, err := NewCard(n, Suite(r))
+
+	if err != nil {
+		return Card{}, fmt.Errorf("invalid card encoding %s: %w", c, err)
+	}
+
+	return card, nil
+}
+
+// encodes the card as its code, the same as it is sent to bots
+func (c Card) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Encode())
+}
+
+// decodes a card code, an empty string decodes to the nil card
+func (c *Card) UnmarshalJSON(data []byte) error {
+	var code string
+
+	if err := json.Unmarshal(data, &code); err != nil {
+		return fmt.Errorf("unable to decode card: %w", err)
+	}
+
+	if code == "" {
+		*c = Card{}
+		return nil
+	}
+
+	card, err := DecodeCard(code)
+
+	if err != nil {
+		return err
+	}
+
+	*c = card
+	return nil
 }
 
 // encode the sequence into a string eg. 10-R:8-Y:*:10-X
@@ -100,8 +137,13 @@ func EncodeCards(cards []Card) []string {
 }
 
 // takes encoded sequence eg. 10-R:*:8-Y and decodes into a list of Cards
+// an empty string is an empty sequence
 func DecodeSequence(s string) ([]Card, error) {
 
+	if s == "" {
+		return []Card{}, nil
+	}
+
 	cardCodes := strings.Split(s, ":")
 
 	seq := make([]Card, len(cardCodes))
@@ -153,3 +195,25 @@ func DecodeCards(cards []string) ([]Card, error) {
 
 	return seq, errs
 }
+
+// decodes the sequence, panicking if any card is invalid. for sequences known at compile time
+func MustDecodeSequence(s string) []Card {
+	seq, err := DecodeSequence(s)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return seq
+}
+
+// decodes the card, panicking if it is invalid. for cards known at compile time
+func MustDecodeCard(c string) Card {
+	card, err := DecodeCard(c)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return card
+}
@@ -1,6 +1,8 @@
 package game
 
 import (
+	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -90,3 +92,84 @@ func TestSequenceDecode(t *testing.T) {
 		},
 	})
 }
+
+func TestCardDecodeErrors(t *testing.T) {
+
+	for _, code := range []string{"", "5", "2-R", "14-B", "x-R", "5-Z", "5-RR", "5-"} {
+		t.Run("should not decode: "+code, func(t *testing.T) {
+			_, err := DecodeCard(code)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewCard(t *testing.T) {
+
+	cases := []struct {
+		Number int
+		Suite  Suite
+		Valid  bool
+	}{
+		{Number: 3, Suite: SuiteBlue, Valid: true},
+		{Number: 13, Suite: SuiteBlack, Valid: true},
+		{Number: 2, Suite: SuiteRed},
+		{Number: 14, Suite: SuiteRed},
+		{Number: 5, Suite: 'Z'},
+		{Number: 5},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%d-%c", tc.Number, tc.Suite), func(t *testing.T) {
+			card, err := NewCard(tc.Number, tc.Suite)
+
+			if !tc.Valid {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, card.Valid())
+			assert.Equal(t, Card{Number: tc.Number, Suite: tc.Suite}, card)
+		})
+	}
+
+	assert.True(t, CardJoker.Valid())
+	assert.False(t, Card{}.Valid())
+}
+
+func TestSuite(t *testing.T) {
+
+	assert.Equal(t, "G", SuiteGreen.String())
+	assert.Equal(t, "X", fmt.Sprint(SuiteBlack))
+	assert.False(t, Suite('Z').Valid())
+
+	for _, suite := range Suites() {
+		assert.True(t, suite.Valid())
+	}
+
+	// changing the returned suites does not change the deck
+	Suites()[0] = 'Z'
+	assert.Equal(t, SuiteBlue, Suites()[0])
+}
+
+func TestCardJSON(t *testing.T) {
+
+	type request struct {
+		Hand   []Card `json:"hand"`
+		Newest Card   `json:"newest"`
+	}
+
+	req := request{Hand: []Card{CardJoker, {Number: 10, Suite: SuiteRed}}}
+
+	out, err := json.Marshal(req)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"hand": ["*", "10-R"], "newest": ""}`, string(out))
+
+	var decoded request
+	require.NoError(t, json.Unmarshal(out, &decoded))
+	assert.Equal(t, req, decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"hand": ["99-Z"]}`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"hand": [10]}`), &decoded))
+}
@@ -0,0 +1,395 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/referee"
+)
+
+// Engine plays full matches of five crowns between bots
+// the engine owns the deck, the discard pile and every hand. bots only ever see what the protocol gives them
+
+const (
+	// the rounds played under the house rules
+	FirstRound = 3
+	LastRound  = 13
+
+	MinPlayers = 2
+	MaxPlayers = 7
+
+	// number of turns after which a round is abandoned if nobody has gone out
+	DefaultMaxTurns = 1000
+)
+
+type Player struct {
+	Name string
+	Bot  bots.Bot
+}
+
+type Engine struct {
+	players []Player
+	seed    uint64
+
+	// maximum number of turns in a round before it is scored without anyone going out
+	MaxTurns int
+	// the rules the match is played with. every request carries them so bots can follow the same rules
+	Rules game.RuleSet
+	// when set, every request and response is recorded to the log
+	Log *MatchLog
+
+	// the round currently being played
+	current *roundState
+}
+
+type RoundResult struct {
+	Round  int   `json:"round"`
+	Scores []int `json:"scores"`
+	// index of the player who went out first, -1 if nobody went out
+	WentOut int `json:"wentOut"`
+	Turns   int `json:"turns"`
+}
+
+type MatchResult struct {
+	Players []string      `json:"players"`
+	Rounds  []RoundResult `json:"rounds"`
+	Totals  []int         `json:"totals"`
+}
+
+// every bot is refereed so that illegal responses end the match rather than corrupting the result
+// a match is reproducible from the seed and the players, as long as the bots are deterministic
+func NewEngine(players []Player, seed uint64) *Engine {
+	e := &Engine{
+		players:  make([]Player, len(players)),
+		seed:     seed,
+		MaxTurns: DefaultMaxTurns,
+		Rules:    game.HouseRules,
+	}
+
+	for i, player := range players {
+		// the recorder sits inside the referee so the log keeps responses which were rejected
+		e.players[i] = Player{
+			Name: player.Name,
+			Bot: referee.NewReferee(&recorder{
+				engine: e,
+				seat:   i,
+				bot:    player.Bot,
+			}, referee.ModeReject),
+		}
+	}
+
+	return e
+}
+
+// plays every round of the rules, usually 3 to 13, and totals the scores
+func (e *Engine) Play() (MatchResult, error) {
+
+	if len(e.players) < MinPlayers || len(e.players) > MaxPlayers {
+		return MatchResult{}, fmt.Errorf("invalid number of players: %d", len(e.players))
+	}
+
+	if err := e.Rules.Validate(); err != nil {
+		return MatchResult{}, fmt.Errorf("invalid rules: %w", err)
+	} else if e.Rules.DeckSize() <= len(e.players)*e.Rules.LastRound {
+		return MatchResult{}, fmt.Errorf("deck of %d cards is too small to deal %d players", e.Rules.DeckSize(), len(e.players))
+	}
+
+	result := MatchResult{
+		Players: make([]string, len(e.players)),
+		Rounds:  make([]RoundResult, 0, e.Rules.Rounds()),
+		Totals:  make([]int, len(e.players)),
+	}
+
+	for i, player := range e.players {
+		result.Players[i] = player.Name
+	}
+
+	if e.Log != nil {
+		e.Log.Seed = e.seed
+		e.Log.MaxTurns = e.MaxTurns
+		e.Log.Rules = &e.Rules
+		e.Log.Players = result.Players
+	}
+
+	for round := e.Rules.FirstRound; round <= e.Rules.LastRound; round++ {
+		roundResult, err := e.PlayRound(round)
+
+		if err != nil {
+			err = fmt.Errorf("unable to play round %d: %w", round, err)
+
+			if e.Log != nil {
+				e.Log.Error = err.Error()
+			}
+
+			return result, err
+		}
+
+		for i, score := range roundResult.Scores {
+			result.Totals[i] += score
+		}
+
+		result.Rounds = append(result.Rounds, roundResult)
+	}
+
+	if e.Log != nil {
+		e.Log.Result = &result
+	}
+
+	return result, nil
+}
+
+// returns the index of the players with the lowest total score
+func (m MatchResult) Winners() []int {
+	winners := make([]int, 0, 1)
+
+	for i, total := range m.Totals {
+		if len(winners) == 0 || total < m.Totals[winners[0]] {
+			winners = []int{i}
+		} else if total == m.Totals[winners[0]] {
+			winners = append(winners, i)
+		}
+	}
+
+	return winners
+}
+
+type roundState struct {
+	round   int
+	turn    int
+	rand    *rand.Rand
+	deck    *game.Deck
+	discard []game.Card // top-most card is at index 0
+	hands   [][]game.Card
+	// the latest sequences each player has arranged their hand into
+	sequences [][][]game.Card
+	// every turn played this round, with absolute seats
+	history []bots.OpponentTurn
+}
+
+// deals and plays a single round
+// the first player rotates each round
+func (e *Engine) PlayRound(round int) (RoundResult, error) {
+
+	state := e.deal(round)
+
+	wentOut := -1
+
+	for seat := (round - e.Rules.FirstRound) % len(e.players); seat != wentOut; seat = (seat + 1) % len(e.players) {
+
+		if state.turn >= e.MaxTurns {
+			break
+		}
+
+		state.turn += 1
+
+		flop, err := e.playTurn(state, seat, wentOut != -1)
+
+		if err != nil {
+			return RoundResult{}, fmt.Errorf("turn %d: %w", state.turn, err)
+		}
+
+		if flop && wentOut == -1 {
+			wentOut = seat
+		}
+	}
+
+	scores := make([]int, len(e.players))
+
+	for i := range e.players {
+		if i != wentOut {
+			scores[i] = e.Rules.ScorePenalty(state.sequences[i], round)
+		}
+	}
+
+	return RoundResult{
+		Round:   round,
+		Scores:  scores,
+		WentOut: wentOut,
+		Turns:   state.turn,
+	}, nil
+}
+
+func (e *Engine) deal(round int) *roundState {
+	r := game.RoundRand(e.seed, round)
+
+	deck := e.Rules.NewDeck()
+	deck.Shuffle(r)
+
+	state := &roundState{
+		round:     round,
+		rand:      r,
+		deck:      deck,
+		hands:     make([][]game.Card, len(e.players)),
+		sequences: make([][][]game.Card, len(e.players)),
+	}
+
+	for range round {
+		for i := range e.players {
+			// the rules are checked to have enough cards to deal every round
+			card, _ := deck.Draw()
+			state.hands[i] = append(state.hands[i], card)
+		}
+	}
+
+	// until a player has arranged their hand, every card counts towards their score
+	for i, hand := range state.hands {
+		for _, card := range hand {
+			state.sequences[i] = append(state.sequences[i], []game.Card{card})
+		}
+	}
+
+	// flip the top card to start the discard pile
+	top, _ := deck.Draw()
+	state.discard = []game.Card{top}
+
+	return state
+}
+
+// asks the player to draw then discard
+// returns true if the player has gone out
+func (e *Engine) playTurn(state *roundState, seat int, lastTurn bool) (bool, error) {
+	player := e.players[seat]
+	e.current = state
+
+	req := bots.BotRequest{
+		Action:      bots.ActionDraw,
+		Hand:        slices.Clone(state.hands[seat]),
+		Discard:     slices.Clone(state.discard),
+		PlayerCount: len(e.players),
+		Round:       state.round,
+		LastTurn:    lastTurn,
+		Turn:        state.turn,
+		DeckCount:   state.deck.Len(),
+		History:     state.historyFor(seat, len(e.players)),
+		Rules:       &e.Rules,
+	}
+
+	drawRes, err := player.Bot.Draw(req)
+
+	if err != nil {
+		return false, fmt.Errorf("player %s failed to draw: %w", player.Name, err)
+	}
+
+	var card game.Card
+	switch drawRes.Stack {
+	case bots.StackDeck:
+		card, err = state.drawFromDeck()
+	case bots.StackDiscard:
+		card, err = state.drawFromDiscard()
+	default:
+		err = fmt.Errorf("unknown stack: %s", drawRes.Stack)
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("player %s unable to draw: %w", player.Name, err)
+	}
+
+	state.hands[seat] = append(state.hands[seat], card)
+
+	req.Action = bots.ActionDiscard
+	req.Hand = slices.Clone(state.hands[seat])
+	req.Discard = slices.Clone(state.discard)
+	req.NewestCard = card
+	req.DeckCount = state.deck.Len()
+
+	discardRes, err := player.Bot.Discard(req)
+
+	if err != nil {
+		return false, fmt.Errorf("player %s failed to discard: %w", player.Name, err)
+	}
+
+	discarded, err := game.DecodeCard(discardRes.Card)
+
+	if err != nil {
+		return false, fmt.Errorf("player %s discarded an invalid card: %w", player.Name, err)
+	}
+
+	idx := slices.Index(state.hands[seat], discarded)
+
+	if idx == -1 {
+		return false, fmt.Errorf("player %s discarded a card not in their hand: %s", player.Name, discardRes.Card)
+	}
+
+	state.hands[seat] = slices.Delete(state.hands[seat], idx, idx+1)
+	state.discard = slices.Insert(state.discard, 0, discarded)
+
+	seqs, err := decodeSequences(discardRes.Sequences)
+
+	if err != nil {
+		return false, fmt.Errorf("player %s returned invalid sequences: %w", player.Name, err)
+	}
+
+	state.sequences[seat] = seqs
+
+	turn := bots.OpponentTurn{
+		Seat:    seat,
+		Turn:    state.turn,
+		Stack:   drawRes.Stack,
+		Discard: discarded,
+	}
+
+	if drawRes.Stack == bots.StackDiscard {
+		turn.Drawn = card
+	}
+
+	state.history = append(state.history, turn)
+
+	return discardRes.Flop, nil
+}
+
+// returns the turns played by everyone except the seat, with seats relative to it
+func (s *roundState) historyFor(seat int, players int) []bots.OpponentTurn {
+	history := make([]bots.OpponentTurn, 0, len(s.history))
+
+	for _, turn := range s.history {
+		if turn.Seat == seat {
+			continue
+		}
+
+		turn.Seat = (turn.Seat - seat + players) % players
+		history = append(history, turn)
+	}
+
+	return history
+}
+
+// takes the top card from the deck
+// when the deck runs out, the discard pile (except the top card) is shuffled to form a new deck
+func (s *roundState) drawFromDeck() (game.Card, error) {
+	if s.deck.Len() == 0 && len(s.discard) > 1 {
+		s.deck = game.NewDeckFromCards(slices.Clone(s.discard[1:]))
+		s.deck.Shuffle(s.rand)
+		s.discard = s.discard[:1]
+	}
+
+	return s.deck.Draw()
+}
+
+func (s *roundState) drawFromDiscard() (game.Card, error) {
+	if len(s.discard) == 0 {
+		return game.Card{}, errors.New("discard pile is empty")
+	}
+
+	card := s.discard[0]
+	s.discard = s.discard[1:]
+
+	return card, nil
+}
+
+func decodeSequences(seqs [][]string) ([][]game.Card, error) {
+	out := make([][]game.Card, len(seqs))
+
+	var errs error
+	for i, seq := range seqs {
+		cards, err := game.DecodeCards(seq)
+
+		out[i] = cards
+		errs = errors.Join(errs, err)
+	}
+
+	return out, errs
+}
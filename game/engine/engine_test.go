@@ -0,0 +1,203 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/bigbrainbot"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+func TestEnginePlay(t *testing.T) {
+
+	e := NewEngine([]Player{
+		{Name: "grugbot", Bot: grugbot.NewGrugBot()},
+		{Name: "bigbrainbot", Bot: bigbrainbot.NewBigBrainBot()},
+	}, 1)
+
+	res, err := e.Play()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"grugbot", "bigbrainbot"}, res.Players)
+	require.Len(t, res.Rounds, 11)
+
+	totals := make([]int, 2)
+	for i, round := range res.Rounds {
+		assert.Equal(t, FirstRound+i, round.Round)
+
+		if round.WentOut != -1 {
+			assert.Equal(t, 0, round.Scores[round.WentOut])
+		}
+
+		for p, score := range round.Scores {
+			assert.GreaterOrEqual(t, score, 0)
+			totals[p] += score
+		}
+	}
+
+	assert.Equal(t, totals, res.Totals)
+}
+
+func TestEngineRules(t *testing.T) {
+
+	short := game.OfficialRules
+	short.FirstRound = 5
+	short.LastRound = 7
+
+	e := NewEngine([]Player{
+		{Name: "grugbot", Bot: grugbot.NewGrugBot()},
+		{Name: "bigbrainbot", Bot: bigbrainbot.NewBigBrainBot()},
+	}, 1)
+	e.Rules = short
+	e.Log = &MatchLog{}
+
+	res, err := e.Play()
+
+	require.NoError(t, err)
+	require.Len(t, res.Rounds, 3)
+	assert.Equal(t, 5, res.Rounds[0].Round)
+
+	// every request tells the bot which rules are being played
+	for _, event := range e.Log.Events {
+		assert.Equal(t, short, event.Request.RuleSet())
+	}
+
+	replayed, err := Replay(e.Log)
+	require.NoError(t, err)
+	assert.Equal(t, res, replayed)
+
+	// seven players need more cards than a single deck has
+	small := game.HouseRules
+	small.DeckCopies = 1
+
+	players := make([]Player, MaxPlayers)
+	for i := range players {
+		players[i] = Player{Name: "grugbot", Bot: grugbot.NewGrugBot()}
+	}
+
+	e = NewEngine(players, 1)
+	e.Rules = small
+
+	_, err = e.Play()
+	assert.ErrorContains(t, err, "too small")
+}
+
+func TestEnginePlayerCount(t *testing.T) {
+
+	e := NewEngine([]Player{
+		{Name: "grugbot", Bot: grugbot.NewGrugBot()},
+	}, 1)
+
+	_, err := e.Play()
+
+	assert.Error(t, err)
+}
+
+// cheatBot discards a card that it was never dealt
+type cheatBot struct{}
+
+func (cheatBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	return bots.DrawResponse{Action: bots.ActionDraw, Stack: bots.StackDeck}, nil
+}
+
+func (cheatBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	return bots.DiscardResponse{Action: bots.ActionDiscard, Card: "99-Z"}, nil
+}
+
+func (cheatBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	return bots.ScoreResponse{Action: bots.ActionScore}, nil
+}
+
+func TestEngineRejectsInvalidDiscard(t *testing.T) {
+
+	e := NewEngine([]Player{
+		{Name: "cheatbot", Bot: cheatBot{}},
+		{Name: "grugbot", Bot: grugbot.NewGrugBot()},
+	}, 1)
+
+	_, err := e.PlayRound(FirstRound)
+
+	assert.ErrorContains(t, err, "cheatbot")
+}
+
+func TestWinners(t *testing.T) {
+
+	res := MatchResult{
+		Totals: []int{40, 12, 12, 90},
+	}
+
+	assert.Equal(t, []int{1, 2}, res.Winners())
+}
+
+func TestEngineIsReproducible(t *testing.T) {
+
+	play := func() MatchResult {
+		e := NewEngine([]Player{
+			{Name: "grugbot", Bot: grugbot.NewGrugBot()},
+			{Name: "bigbrainbot", Bot: bigbrainbot.NewBigBrainBot()},
+		}, 99)
+
+		res, err := e.Play()
+		require.NoError(t, err)
+
+		return res
+	}
+
+	assert.Equal(t, play(), play())
+}
+
+func TestEngineHistory(t *testing.T) {
+
+	e := NewEngine([]Player{
+		{Name: "grugbot", Bot: grugbot.NewGrugBot()},
+		{Name: "bigbrainbot", Bot: bigbrainbot.NewBigBrainBot()},
+		{Name: "grugbot", Bot: grugbot.NewGrugBot()},
+	}, 3)
+	e.Log = &MatchLog{}
+
+	_, err := e.PlayRound(FirstRound)
+	require.NoError(t, err)
+
+	// the seat and discard of every turn
+	seats := make(map[int]int)
+	discards := make(map[int]string)
+
+	for _, event := range e.Log.Events {
+		if event.Discard != nil {
+			seats[event.Turn] = event.Seat
+			discards[event.Turn] = event.Discard.Card
+		}
+	}
+
+	for _, event := range e.Log.Events {
+		if event.Request.Action != bots.ActionDraw {
+			continue
+		}
+
+		others := 0
+		for turn := 1; turn < event.Turn; turn++ {
+			if seats[turn] != event.Seat {
+				others += 1
+			}
+		}
+
+		require.Len(t, event.Request.History, others)
+		assert.Equal(t, event.Turn, event.Request.Turn)
+		assert.Positive(t, event.Request.DeckCount)
+
+		for _, turn := range event.Request.History {
+			assert.Equal(t, (seats[turn.Turn]-event.Seat+3)%3, turn.Seat)
+			assert.NotZero(t, turn.Seat)
+			assert.Equal(t, discards[turn.Turn], turn.Discard.Encode())
+
+			if turn.Stack == bots.StackDeck {
+				assert.Empty(t, turn.Drawn)
+			} else {
+				assert.NotEmpty(t, turn.Drawn)
+			}
+		}
+	}
+}
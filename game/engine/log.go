@@ -0,0 +1,238 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+// MatchLog records every request sent to a bot and the response it gave
+// along with the seed, it is enough to replay a match exactly
+
+type MatchLog struct {
+	Seed     uint64 `json:"seed"`
+	MaxTurns int    `json:"maxTurns"`
+	// logs recorded before rule sets were added have none, and were played with the house rules
+	Rules   *game.RuleSet `json:"rules,omitempty"`
+	Players []string      `json:"players"`
+	Events  []Event       `json:"events"`
+	Result  *MatchResult  `json:"result,omitempty"`
+	// the reason the match ended early
+	Error string `json:"error,omitempty"`
+}
+
+type Event struct {
+	Round   int                   `json:"round"`
+	Turn    int                   `json:"turn"`
+	Seat    int                   `json:"seat"`
+	Request bots.BotRequest       `json:"request"`
+	Draw    *bots.DrawResponse    `json:"draw,omitempty"`
+	Discard *bots.DiscardResponse `json:"discard,omitempty"`
+	Score   *bots.ScoreResponse   `json:"score,omitempty"`
+	// set when the bot returned an error instead of a response
+	Error string `json:"error,omitempty"`
+}
+
+func WriteLog(w io.Writer, log *MatchLog) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(log); err != nil {
+		return fmt.Errorf("unable to encode match log: %w", err)
+	}
+
+	return nil
+}
+
+func ReadLog(r io.Reader) (*MatchLog, error) {
+	var log MatchLog
+
+	if err := json.NewDecoder(r).Decode(&log); err != nil {
+		return nil, fmt.Errorf("unable to decode match log: %w", err)
+	}
+
+	return &log, nil
+}
+
+// returns the events for a single turn
+func (l *MatchLog) Turn(round, turn int) []Event {
+	events := make([]Event, 0, 2)
+
+	for _, event := range l.Events {
+		if event.Round == round && event.Turn == turn {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// recorder adds every call to a bot to the engine's log
+type recorder struct {
+	engine *Engine
+	seat   int
+	bot    bots.Bot
+}
+
+func (r *recorder) record(req bots.BotRequest, err error) *Event {
+	if r.engine.Log == nil {
+		return nil
+	}
+
+	event := Event{
+		Seat:    r.seat,
+		Request: req,
+	}
+
+	if state := r.engine.current; state != nil {
+		event.Round = state.round
+		event.Turn = state.turn
+	}
+
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	r.engine.Log.Events = append(r.engine.Log.Events, event)
+
+	return &r.engine.Log.Events[len(r.engine.Log.Events)-1]
+}
+
+func (r *recorder) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	res, err := r.bot.Draw(req)
+
+	if event := r.record(req, err); event != nil && err == nil {
+		event.Draw = &res
+	}
+
+	return res, err
+}
+
+func (r *recorder) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	res, err := r.bot.Discard(req)
+
+	if event := r.record(req, err); event != nil && err == nil {
+		event.Discard = &res
+	}
+
+	return res, err
+}
+
+func (r *recorder) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	res, err := r.bot.Score(req)
+
+	if event := r.record(req, err); event != nil && err == nil {
+		event.Score = &res
+	}
+
+	return res, err
+}
+
+// replays a logged match, answering each request with the logged response
+// returns an error if the engine asks a bot something different from what was logged
+func Replay(log *MatchLog) (MatchResult, error) {
+	players := make([]Player, len(log.Players))
+
+	script := &script{events: log.Events}
+
+	for i, name := range log.Players {
+		players[i] = Player{
+			Name: name,
+			Bot: &scriptedBot{
+				script: script,
+				seat:   i,
+			},
+		}
+	}
+
+	e := NewEngine(players, log.Seed)
+	e.MaxTurns = log.MaxTurns
+
+	if log.Rules != nil {
+		e.Rules = *log.Rules
+	}
+
+	return e.Play()
+}
+
+type script struct {
+	events []Event
+	next   int
+}
+
+type scriptedBot struct {
+	script *script
+	seat   int
+}
+
+var ErrReplayDiverged = errors.New("replay diverged from the log")
+
+// returns the next logged event after checking it matches the request
+func (b *scriptedBot) next(req bots.BotRequest) (Event, error) {
+	if b.script.next >= len(b.script.events) {
+		return Event{}, fmt.Errorf("%w: no more events", ErrReplayDiverged)
+	}
+
+	event := b.script.events[b.script.next]
+	b.script.next += 1
+
+	// requests in older logs do not carry the rules
+	if event.Request.Rules == nil {
+		req.Rules = nil
+	}
+
+	// compare the encoded requests so a log read from disk matches the live request
+	logged, _ := json.Marshal(event.Request)
+	actual, _ := json.Marshal(req)
+
+	if event.Seat != b.seat || !bytes.Equal(logged, actual) {
+		return Event{}, fmt.Errorf("%w: round %d turn %d", ErrReplayDiverged, event.Round, event.Turn)
+	}
+
+	if event.Error != "" {
+		return Event{}, errors.New(event.Error)
+	}
+
+	return event, nil
+}
+
+func (b *scriptedBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	event, err := b.next(req)
+
+	if err != nil {
+		return bots.DrawResponse{}, err
+	} else if event.Draw == nil {
+		return bots.DrawResponse{}, fmt.Errorf("%w: round %d turn %d has no draw response", ErrReplayDiverged, event.Round, event.Turn)
+	}
+
+	return *event.Draw, nil
+}
+
+func (b *scriptedBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	event, err := b.next(req)
+
+	if err != nil {
+		return bots.DiscardResponse{}, err
+	} else if event.Discard == nil {
+		return bots.DiscardResponse{}, fmt.Errorf("%w: round %d turn %d has no discard response", ErrReplayDiverged, event.Round, event.Turn)
+	}
+
+	return *event.Discard, nil
+}
+
+func (b *scriptedBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	event, err := b.next(req)
+
+	if err != nil {
+		return bots.ScoreResponse{}, err
+	} else if event.Score == nil {
+		return bots.ScoreResponse{}, fmt.Errorf("%w: round %d turn %d has no score response", ErrReplayDiverged, event.Round, event.Turn)
+	}
+
+	return *event.Score, nil
+}
@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/bots/smoothbrainbot"
+)
+
+func recordMatch(t *testing.T) (*MatchLog, MatchResult) {
+	e := NewEngine([]Player{
+		{Name: "smoothbrainbot", Bot: smoothbrainbot.NewSmoothBrainBot()},
+		{Name: "grugbot", Bot: grugbot.NewGrugBot()},
+	}, 5)
+	e.MaxTurns = 40
+	e.Log = &MatchLog{}
+
+	res, err := e.Play()
+	require.NoError(t, err)
+
+	return e.Log, res
+}
+
+func TestMatchLog(t *testing.T) {
+
+	log, res := recordMatch(t)
+
+	assert.Equal(t, uint64(5), log.Seed)
+	assert.Equal(t, []string{"smoothbrainbot", "grugbot"}, log.Players)
+	assert.Equal(t, &res, log.Result)
+
+	// the first turn of the first round is a draw and a discard by the first player
+	turn := log.Turn(FirstRound, 1)
+	require.Len(t, turn, 2)
+	assert.NotNil(t, turn[0].Draw)
+	assert.NotNil(t, turn[1].Discard)
+	assert.Equal(t, 0, turn[0].Seat)
+	assert.Len(t, turn[1].Request.Hand, FirstRound+1)
+}
+
+func TestReplay(t *testing.T) {
+
+	log, res := recordMatch(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteLog(&buf, log))
+
+	read, err := ReadLog(&buf)
+	require.NoError(t, err)
+
+	// smoothbrainbot plays randomly, but the replay uses its logged responses
+	replayed, err := Replay(read)
+
+	require.NoError(t, err)
+	assert.Equal(t, res, replayed)
+}
+
+func TestReplayDiverged(t *testing.T) {
+
+	log, _ := recordMatch(t)
+
+	// a different seed deals different hands to the ones in the log
+	log.Seed = 6
+
+	_, err := Replay(log)
+
+	assert.ErrorIs(t, err, ErrReplayDiverged)
+}
@@ -1,28 +1,77 @@
 package game
 
+import "fmt"
+
+// Suite is the colour of a card, written as a single letter in card codes
 type Suite rune
 
-var (
-	SuiteBlue   rune = 'B'
-	SuiteRed    rune = 'R'
-	SuiteYellow rune = 'Y'
-	SuiteGreen  rune = 'G'
-	SuiteBlack  rune = 'X'
-	Suites           = []rune{SuiteBlue, SuiteGreen, SuiteBlack, SuiteRed, SuiteYellow}
+const (
+	SuiteBlue   Suite = 'B'
+	SuiteRed    Suite = 'R'
+	SuiteYellow Suite = 'Y'
+	SuiteGreen  Suite = 'G'
+	SuiteBlack  Suite = 'X'
 )
 
+// returns every suite in the order they are dealt into a new deck
+func Suites() []Suite {
+	return []Suite{SuiteBlue, SuiteGreen, SuiteBlack, SuiteRed, SuiteYellow}
+}
+
+// returns the letter of the suite used in card codes eg. "R"
+func (s Suite) String() string {
+	return string(s)
+}
+
+func (s Suite) Valid() bool {
+	switch s {
+	case SuiteBlue, SuiteRed, SuiteYellow, SuiteGreen, SuiteBlack:
+		return true
+	}
+
+	return false
+}
+
+const (
+	// lowest and highest numbers of a suited card
+	MinNumber = 3
+	MaxNumber = 13
+)
+
+// Card is compared by value, so cards with the same number and suite are interchangeable
+// the zero card is no card at all, and encodes as an empty string
 type Card struct {
 	Joker  bool
 	Number int
-	Suite  rune
+	Suite  Suite
 }
 
 var (
 	CardJoker = Card{Joker: true}
 )
 
+// creates a suited card, rejecting numbers and suites which are not in the deck
+func NewCard(number int, suite Suite) (Card, error) {
+	if number < MinNumber || number > MaxNumber {
+		return Card{}, fmt.Errorf("invalid card number: %d", number)
+	} else if !suite.Valid() {
+		return Card{}, fmt.Errorf("invalid card suite: %q", rune(suite))
+	}
+
+	return Card{Number: number, Suite: suite}, nil
+}
+
 func (c Card) IsNil() bool {
-	return !c.Joker && c.Number == 0 && c.Suite == 0
+	return c == Card{}
+}
+
+// determines if the card could have been dealt from a deck
+func (c Card) Valid() bool {
+	if c.Joker {
+		return c.Number == 0 && c.Suite == 0
+	}
+
+	return c.Number >= MinNumber && c.Number <= MaxNumber && c.Suite.Valid()
 }
 
 func (c Card) IsWild(round int) bool {
@@ -60,15 +109,7 @@ func GetSequenceType(seq []Card, round int) SequenceType {
 	return SequenceTypeEither
 }
 
-func CanFlop(seqs [][]Card) bool {
-
-	canFlop := true
-
-	for _, seq := range seqs {
-		if len(seq) < 3 {
-			canFlop = false
-		}
-	}
-
-	return canFlop
+// determines if every sequence is a legal run or set under the house rules, allowing the hand to go out
+func CanFlop(seqs [][]Card, round int) bool {
+	return HouseRules.CanFlop(seqs, round)
 }
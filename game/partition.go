@@ -0,0 +1,355 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Partitioning finds the arrangements of a hand into runs and sets which leave the lowest penalty
+// it searches every way of grouping the natural cards, giving each group the fewest wilds it needs
+// wilds are interchangeable, so two arrangements only differ in how the natural cards are grouped
+
+// Arrangement is a hand split into valid runs and sets, and the cards left over
+type Arrangement struct {
+	Sequences [][]Card
+	Leftover  []Card
+	Penalty   int
+}
+
+// returns the sequences followed by each leftover card on its own, the way bots arrange their hand
+func (a Arrangement) Hand() [][]Card {
+	seqs := make([][]Card, 0, len(a.Sequences)+len(a.Leftover))
+	seqs = append(seqs, a.Sequences...)
+
+	for _, card := range a.Leftover {
+		seqs = append(seqs, []Card{card})
+	}
+
+	return seqs
+}
+
+// finds every arrangement of the hand which leaves the lowest possible penalty under the house rules
+func OptimalArrangements(hand []Card, round int) []Arrangement {
+	return HouseRules.OptimalArrangements(hand, round)
+}
+
+// finds every arrangement of the hand which leaves the lowest possible penalty
+// the most expensive wilds are used first, so a wild is only left over when it is the cheapest
+// sets of the same number are always kept together, as splitting them never lowers the penalty
+func (r RuleSet) OptimalArrangements(hand []Card, round int) []Arrangement {
+	naturals := make([]Card, 0, len(hand))
+	wilds := make([]Card, 0)
+
+	for _, card := range hand {
+		if card.IsWild(round) {
+			wilds = append(wilds, card)
+		} else {
+			naturals = append(naturals, card)
+		}
+	}
+
+	slices.SortFunc(naturals, CompareCard)
+
+	// the most expensive wilds are used first
+	slices.SortFunc(wilds, func(a, b Card) int {
+		if c := r.ScoreCard(b, round) - r.ScoreCard(a, round); c != 0 {
+			return c
+		}
+
+		return CompareCard(a, b)
+	})
+
+	p := &partitioner{
+		rules: r,
+		round: round,
+		wilds: wilds,
+		memo:  make(map[string]partitionResult),
+	}
+
+	res := p.solve(naturals, len(wilds), 0, 0)
+
+	arrangements := make([]Arrangement, 0, len(res.options))
+	seen := make(map[string]bool)
+
+	for _, option := range res.options {
+		arrangement := p.arrange(option)
+		key := arrangementKey(arrangement)
+
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		arrangements = append(arrangements, arrangement)
+	}
+
+	return arrangements
+}
+
+// returns a single arrangement with the lowest possible penalty under the house rules
+func OptimalArrangement(hand []Card, round int) Arrangement {
+	return HouseRules.OptimalArrangement(hand, round)
+}
+
+// returns a single arrangement with the lowest possible penalty
+func (r RuleSet) OptimalArrangement(hand []Card, round int) Arrangement {
+	return r.OptimalArrangements(hand, round)[0]
+}
+
+type partitioner struct {
+	rules RuleSet
+	round int
+	// every wild in the hand, most expensive first
+	wilds []Card
+	memo  map[string]partitionResult
+}
+
+type partitionGroup struct {
+	naturals []Card
+	// number of wilds needed to make the group valid
+	wilds int
+}
+
+// a way of arranging the remaining natural cards
+type partitionOption struct {
+	groups   []partitionGroup
+	leftover []Card
+}
+
+type partitionResult struct {
+	penalty int
+	options []partitionOption
+}
+
+// finds the best ways to arrange the remaining natural cards, which are sorted
+// closed has a bit set for each number which already has a set
+// room is the number of spare wilds the groups so far could take
+func (p *partitioner) solve(remaining []Card, wilds int, closed uint16, room int) partitionResult {
+
+	if len(remaining) == 0 {
+		return partitionResult{
+			penalty: p.wildPenalty(wilds, room),
+			options: []partitionOption{{}},
+		}
+	}
+
+	key := fmt.Sprintf("%s|%d|%d|%d", strings.Join(EncodeCards(remaining), ":"), wilds, closed, min(room, wilds))
+
+	if res, ok := p.memo[key]; ok {
+		return res
+	}
+
+	best := partitionResult{penalty: math.MaxInt}
+
+	consider := func(penalty int, group *partitionGroup, leftover *Card, res partitionResult) {
+		penalty += res.penalty
+
+		if penalty > best.penalty {
+			return
+		} else if penalty < best.penalty {
+			best = partitionResult{penalty: penalty}
+		}
+
+		for _, option := range res.options {
+			next := partitionOption{
+				groups:   option.groups,
+				leftover: option.leftover,
+			}
+
+			if group != nil {
+				next.groups = append([]partitionGroup{*group}, option.groups...)
+			}
+
+			if leftover != nil {
+				next.leftover = append([]Card{*leftover}, option.leftover...)
+			}
+
+			best.options = append(best.options, next)
+		}
+	}
+
+	// the lowest remaining card is either left over, or the lowest card of a set or run
+	card := remaining[0]
+	rest := remaining[1:]
+
+	consider(p.rules.ScoreCard(card, p.round), nil, &card, p.solve(rest, wilds, closed, room))
+
+	// sets of the card's number, using any of the other cards with that number
+	if bit := uint16(1) << card.Number; closed&bit == 0 {
+		same := make([]int, 0)
+
+		for i, other := range rest {
+			if other.Number == card.Number {
+				same = append(same, i)
+			}
+		}
+
+		for _, picked := range subsets(rest, same) {
+			naturals := append([]Card{card}, pick(rest, picked)...)
+			need := max(p.rules.MinSequenceLength-len(naturals), 0)
+
+			if need > wilds {
+				continue
+			}
+
+			group := partitionGroup{naturals: naturals, wilds: need}
+			// a set can take any number of wilds
+			consider(0, &group, nil, p.solve(without(rest, picked), wilds-need, closed|bit, len(p.wilds)))
+		}
+	}
+
+	// runs of the card's suite starting from the card. a run of one natural card is the same as a set
+	higher := make([]int, 0)
+
+	for i, other := range rest {
+		if other.Suite == card.Suite && other.Number > card.Number && (len(higher) == 0 || rest[higher[len(higher)-1]] != other) {
+			higher = append(higher, i)
+		}
+	}
+
+	for _, picked := range subsets(rest, higher) {
+		if len(picked) == 0 {
+			continue
+		}
+
+		naturals := append([]Card{card}, pick(rest, picked)...)
+		numbers := make([]int, len(naturals))
+
+		for i, natural := range naturals {
+			numbers[i] = natural.Number
+		}
+
+		need := max(p.rules.span(numbers)-len(naturals), p.rules.MinSequenceLength-len(naturals))
+		length := len(naturals) + need
+
+		if need > wilds || length > MaxRunLength {
+			continue
+		}
+
+		group := partitionGroup{naturals: naturals, wilds: need}
+		consider(0, &group, nil, p.solve(without(rest, picked), wilds-need, closed, room+MaxRunLength-length))
+	}
+
+	p.memo[key] = best
+
+	return best
+}
+
+// the penalty of the wilds which are not needed by any group
+// they can make a group of their own, or join the groups with room for them
+func (p *partitioner) wildPenalty(wilds int, room int) int {
+	if wilds <= room || wilds >= p.rules.MinSequenceLength {
+		return 0
+	}
+
+	return p.rules.ScoreSequence(p.wilds[len(p.wilds)-(wilds-room):], p.round)
+}
+
+// assigns the wilds to the groups of an option
+func (p *partitioner) arrange(option partitionOption) Arrangement {
+	wilds := slices.Clone(p.wilds)
+
+	arrangement := Arrangement{
+		Sequences: make([][]Card, len(option.groups)),
+		Leftover:  slices.Clone(option.leftover),
+	}
+
+	for i, group := range option.groups {
+		arrangement.Sequences[i] = append(slices.Clone(group.naturals), wilds[:group.wilds]...)
+		wilds = wilds[group.wilds:]
+	}
+
+	if len(wilds) >= p.rules.MinSequenceLength {
+		arrangement.Sequences = append(arrangement.Sequences, wilds)
+		wilds = nil
+	}
+
+	// spare wilds join the first group with room for them
+	for len(wilds) > 0 {
+		joined := false
+
+		for i, seq := range arrangement.Sequences {
+			if p.rules.ValidateSequence(append(slices.Clone(seq), wilds[0]), p.round) == nil {
+				arrangement.Sequences[i] = append(seq, wilds[0])
+				wilds = wilds[1:]
+				joined = true
+
+				break
+			}
+		}
+
+		if !joined {
+			break
+		}
+	}
+
+	arrangement.Leftover = append(arrangement.Leftover, wilds...)
+	arrangement.Penalty = p.rules.ScoreSequence(arrangement.Leftover, p.round)
+
+	return arrangement
+}
+
+// returns every subset of the given indexes, skipping subsets which pick identical cards differently
+func subsets(cards []Card, indexes []int) [][]int {
+	out := [][]int{{}}
+
+	for n, idx := range indexes {
+		size := len(out)
+
+		for i := range size {
+			subset := out[i]
+
+			// an identical card can only be picked if the copy before it was picked
+			if n > 0 && cards[indexes[n-1]] == cards[idx] && (len(subset) == 0 || subset[len(subset)-1] != indexes[n-1]) {
+				continue
+			}
+
+			out = append(out, append(slices.Clone(subset), idx))
+		}
+	}
+
+	return out
+}
+
+func pick(cards []Card, indexes []int) []Card {
+	out := make([]Card, len(indexes))
+
+	for i, idx := range indexes {
+		out[i] = cards[idx]
+	}
+
+	return out
+}
+
+// returns the cards without the given indexes, which are sorted
+func without(cards []Card, indexes []int) []Card {
+	out := make([]Card, 0, len(cards)-len(indexes))
+
+	for i, card := range cards {
+		if _, found := slices.BinarySearch(indexes, i); !found {
+			out = append(out, card)
+		}
+	}
+
+	return out
+}
+
+// identifies an arrangement regardless of the order of its sequences and cards
+func arrangementKey(a Arrangement) string {
+	seqs := make([]string, len(a.Sequences))
+
+	for i, seq := range a.Sequences {
+		cards := slices.Clone(seq)
+		slices.SortFunc(cards, CompareCard)
+		seqs[i] = EncodeSequence(cards)
+	}
+
+	slices.Sort(seqs)
+
+	leftover := slices.Clone(a.Leftover)
+	slices.SortFunc(leftover, CompareCard)
+
+	return strings.Join(seqs, "|") + "/" + EncodeSequence(leftover)
+}
@@ -0,0 +1,172 @@
+package game
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptimalArrangements(t *testing.T) {
+
+	// expected arrangements are written as their sorted sequences separated by "|", then "/" and the sorted leftovers
+	cases := []struct {
+		Hand     string
+		Round    int
+		Penalty  int
+		Expected []string
+	}{
+		{
+			Hand:     "5-B:*:5-R:4-B:6-B",
+			Round:    10,
+			Penalty:  5,
+			Expected: []string{"4-B:5-B:6-B:*/5-R"},
+		},
+		{
+			// taking the run first would leave both sevens
+			Hand:     "5-R:6-R:7-R:7-B:7-Y",
+			Round:    3,
+			Penalty:  11,
+			Expected: []string{"7-B:7-R:7-Y/5-R:6-R"},
+		},
+		{
+			Hand:     "9-R:9-B:9-Y:9-G:10-R:11-R",
+			Round:    3,
+			Penalty:  0,
+			Expected: []string{"9-B:9-G:9-Y|9-R:10-R:11-R/"},
+		},
+		{
+			// the joker can complete either the run or the set
+			Hand:    "3-B:5-B:4-R:4-Y:*",
+			Round:   10,
+			Penalty: 8,
+			Expected: []string{
+				"3-B:5-B:*/4-R:4-Y",
+				"4-R:4-Y:*/3-B:5-B",
+			},
+		},
+		{
+			Hand:     "3-X:8-B:13-Y",
+			Round:    4,
+			Penalty:  24,
+			Expected: []string{"/8-B:3-X:13-Y"},
+		},
+		{
+			Hand:     "*:8-B:12-R:12-Y",
+			Round:    6,
+			Penalty:  8,
+			Expected: []string{"12-R:12-Y:*/8-B"},
+		},
+		{
+			Hand:     "*:*:7-B",
+			Round:    7,
+			Penalty:  0,
+			Expected: []string{"7-B:*:*/"},
+		},
+		{
+			// the joker fills the gap, and the spare wild joins the run
+			Hand:     "3-G:5-G:6-G:*:4-Y",
+			Round:    4,
+			Penalty:  0,
+			Expected: []string{"3-G:5-G:6-G:4-Y:*/"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Hand, func(t *testing.T) {
+			hand, err := DecodeCards(strings.Split(c.Hand, ":"))
+			require.NoError(t, err)
+
+			arrangements := OptimalArrangements(hand, c.Round)
+
+			actual := make([]string, len(arrangements))
+			for i, a := range arrangements {
+				assert.Equal(t, c.Penalty, a.Penalty)
+				assert.NoError(t, ValidateSequences(a.Sequences, c.Round))
+				assert.Equal(t, c.Penalty, ScorePenalty(a.Hand(), c.Round))
+
+				actual[i] = arrangementKey(a)
+			}
+
+			assert.ElementsMatch(t, c.Expected, actual)
+		})
+	}
+}
+
+// compares the partitioner against trying every way of splitting small hands
+func TestOptimalArrangementsBruteForce(t *testing.T) {
+
+	wrap := HouseRules
+	wrap.Name = "wrap"
+	wrap.WrapRuns = true
+
+	long := OfficialRules
+	long.Name = "long"
+	long.MinSequenceLength = 4
+
+	for _, rules := range []RuleSet{HouseRules, OfficialRules, wrap, long} {
+		t.Run(rules.Name, func(t *testing.T) {
+			r := rand.New(rand.NewPCG(1, 2))
+
+			for range 300 {
+				deck := rules.NewDeck()
+				deck.Shuffle(r)
+
+				round := 3 + r.IntN(11)
+				hand := deck.Cards()[:3+r.IntN(5)]
+
+				arrangements := rules.OptimalArrangements(hand, round)
+				require.NotEmpty(t, arrangements)
+
+				expected := bruteForcePenalty(rules, hand, round)
+
+				for _, a := range arrangements {
+					require.Equal(t, expected, a.Penalty, EncodeSequence(hand))
+					require.NoError(t, rules.ValidateSequences(a.Sequences, round))
+
+					used := slices.Concat(append(a.Sequences, a.Leftover)...)
+					require.ElementsMatch(t, hand, used)
+				}
+			}
+		})
+	}
+}
+
+// tries every partition of the hand into blocks, each of which is either a valid sequence or left over
+func bruteForcePenalty(rules RuleSet, hand []Card, round int) int {
+	best := rules.ScoreSequence(hand, round)
+
+	var split func(i int, blocks [][]Card)
+	split = func(i int, blocks [][]Card) {
+		if i == len(hand) {
+			best = min(best, rules.ScorePenalty(blocks, round))
+			return
+		}
+
+		for b := range blocks {
+			blocks[b] = append(blocks[b], hand[i])
+			split(i+1, blocks)
+			blocks[b] = blocks[b][:len(blocks[b])-1]
+		}
+
+		split(i+1, append(blocks, []Card{hand[i]}))
+	}
+
+	split(0, nil)
+
+	return best
+}
+
+func BenchmarkOptimalArrangements(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 2))
+
+	for range b.N {
+		deck := NewDeck()
+		deck.Shuffle(r)
+
+		OptimalArrangements(deck.Cards()[:14], 13)
+	}
+}
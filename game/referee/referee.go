@@ -0,0 +1,310 @@
+package referee
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+// Referee wraps a bot and checks every response against the request the bot was given
+// depending on the mode, illegal responses are either rejected with an error or flagged and passed through
+
+type Mode int
+
+const (
+	// illegal responses are returned as a *ViolationError
+	ModeReject Mode = iota
+	// illegal responses are reported to OnViolation and returned unchanged
+	ModeFlag
+)
+
+type Kind string
+
+const (
+	KindWrongAction   Kind = "wrong-action"
+	KindInvalidStack  Kind = "invalid-stack"
+	KindInvalidCard   Kind = "invalid-card"
+	KindMissingCard   Kind = "missing-card"
+	KindDuplicateCard Kind = "duplicate-card"
+	KindInventedCard  Kind = "invented-card"
+	KindFalseFlop     Kind = "false-flop"
+)
+
+type Violation struct {
+	Kind   Kind
+	Card   string
+	Detail string
+}
+
+func (v Violation) String() string {
+	if v.Card != "" {
+		return fmt.Sprintf("%s (%s): %s", v.Kind, v.Card, v.Detail)
+	}
+
+	return fmt.Sprintf("%s: %s", v.Kind, v.Detail)
+}
+
+// ViolationError lists every rule a response broke
+type ViolationError struct {
+	Action     bots.Action
+	Violations []Violation
+}
+
+func (e *ViolationError) Error() string {
+	violations := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		violations[i] = v.String()
+	}
+
+	return fmt.Sprintf("illegal %s response: %s", e.Action, strings.Join(violations, "; "))
+}
+
+type Referee struct {
+	bot  bots.Bot
+	mode Mode
+
+	// called with every illegal response. defaults to logging a warning
+	OnViolation func(err *ViolationError)
+}
+
+func NewReferee(bot bots.Bot, mode Mode) *Referee {
+	return &Referee{
+		bot:  bot,
+		mode: mode,
+		OnViolation: func(err *ViolationError) {
+			slog.Warn("bot returned an illegal response", "err", err)
+		},
+	}
+}
+
+func (r *Referee) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	res, err := r.bot.Draw(req)
+
+	if err != nil {
+		return res, err
+	}
+
+	if err := r.judge(bots.ActionDraw, CheckDraw(req, res)); err != nil {
+		return bots.DrawResponse{}, err
+	}
+
+	return res, nil
+}
+
+func (r *Referee) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	res, err := r.bot.Discard(req)
+
+	if err != nil {
+		return res, err
+	}
+
+	if err := r.judge(bots.ActionDiscard, CheckDiscard(req, res)); err != nil {
+		return bots.DiscardResponse{}, err
+	}
+
+	return res, nil
+}
+
+func (r *Referee) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	res, err := r.bot.Score(req)
+
+	if err != nil {
+		return res, err
+	}
+
+	if err := r.judge(bots.ActionScore, CheckScore(req, res)); err != nil {
+		return bots.ScoreResponse{}, err
+	}
+
+	return res, nil
+}
+
+// returns an error if the response should be rejected
+func (r *Referee) judge(action bots.Action, violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	err := &ViolationError{
+		Action:     action,
+		Violations: violations,
+	}
+
+	if r.mode == ModeReject {
+		return err
+	}
+
+	if r.OnViolation != nil {
+		r.OnViolation(err)
+	}
+
+	return nil
+}
+
+func CheckDraw(req bots.BotRequest, res bots.DrawResponse) []Violation {
+	violations := checkAction(bots.ActionDraw, res.Action)
+
+	switch res.Stack {
+	case bots.StackDeck:
+	case bots.StackDiscard:
+		if len(req.Discard) == 0 {
+			violations = append(violations, Violation{
+				Kind:   KindInvalidStack,
+				Detail: "cannot draw from an empty discard pile",
+			})
+		}
+	default:
+		violations = append(violations, Violation{
+			Kind:   KindInvalidStack,
+			Detail: fmt.Sprintf("unknown stack %q", res.Stack),
+		})
+	}
+
+	return violations
+}
+
+// checks the discarded card came from the hand and the remaining cards are each used exactly once
+func CheckDiscard(req bots.BotRequest, res bots.DiscardResponse) []Violation {
+	violations := checkAction(bots.ActionDiscard, res.Action)
+
+	hand := cardCounts(req.Hand)
+	remaining := cardCounts(req.Hand)
+
+	card, err := game.DecodeCard(res.Card)
+
+	if err != nil {
+		violations = append(violations, Violation{
+			Kind:   KindInvalidCard,
+			Card:   res.Card,
+			Detail: err.Error(),
+		})
+	} else if remaining[card] == 0 {
+		violations = append(violations, Violation{
+			Kind:   KindInventedCard,
+			Card:   res.Card,
+			Detail: "discarded card is not in the hand",
+		})
+	} else {
+		remaining[card] -= 1
+	}
+
+	violations = append(violations, checkSequences(req.RuleSet(), req.Round, hand, remaining, res.Sequences, res.Flop)...)
+
+	return violations
+}
+
+// checks every card in the hand is used exactly once
+func CheckScore(req bots.BotRequest, res bots.ScoreResponse) []Violation {
+	violations := checkAction(bots.ActionScore, res.Action)
+
+	violations = append(violations, checkSequences(req.RuleSet(), req.Round, cardCounts(req.Hand), cardCounts(req.Hand), res.Sequences, res.Flop)...)
+
+	return violations
+}
+
+func checkAction(expected, actual bots.Action) []Violation {
+	if expected == actual {
+		return nil
+	}
+
+	return []Violation{
+		{
+			Kind:   KindWrongAction,
+			Detail: fmt.Sprintf("expected action %q but got %q", expected, actual),
+		},
+	}
+}
+
+// compares the cards used in the sequences to the cards which should be remaining in the hand
+func checkSequences(rules game.RuleSet, round int, hand, remaining map[game.Card]int, sequences [][]string, flop bool) []Violation {
+	violations := make([]Violation, 0)
+
+	used := make(map[game.Card]int)
+	seqs := make([][]game.Card, 0, len(sequences))
+	decoded := true
+
+	for _, seq := range sequences {
+		cards := make([]game.Card, 0, len(seq))
+
+		for _, c := range seq {
+			card, err := game.DecodeCard(c)
+
+			if err != nil {
+				violations = append(violations, Violation{
+					Kind:   KindInvalidCard,
+					Card:   c,
+					Detail: err.Error(),
+				})
+				decoded = false
+				continue
+			}
+
+			used[card] += 1
+			cards = append(cards, card)
+		}
+
+		seqs = append(seqs, cards)
+	}
+
+	// go through the cards in order so violations are reported consistently
+	cards := make([]game.Card, 0, len(used)+len(remaining))
+	for card := range used {
+		cards = append(cards, card)
+	}
+	for card := range remaining {
+		if _, ok := used[card]; !ok {
+			cards = append(cards, card)
+		}
+	}
+
+	slices.SortFunc(cards, game.CompareCard)
+
+	for _, card := range cards {
+		count := used[card]
+
+		if count > remaining[card] && hand[card] == 0 {
+			violations = append(violations, Violation{
+				Kind:   KindInventedCard,
+				Card:   card.Encode(),
+				Detail: "card is not in the hand",
+			})
+		} else if count > remaining[card] {
+			violations = append(violations, Violation{
+				Kind:   KindDuplicateCard,
+				Card:   card.Encode(),
+				Detail: fmt.Sprintf("card is used %d times but only %d are available", count, remaining[card]),
+			})
+		} else if count < remaining[card] {
+			violations = append(violations, Violation{
+				Kind:   KindMissingCard,
+				Card:   card.Encode(),
+				Detail: "card is not in any sequence",
+			})
+		}
+	}
+
+	if flop && decoded {
+		if err := rules.ValidateSequences(seqs, round); err != nil {
+			violations = append(violations, Violation{
+				Kind:   KindFalseFlop,
+				Detail: err.Error(),
+			})
+		}
+	}
+
+	return violations
+}
+
+func cardCounts(cards []game.Card) map[game.Card]int {
+	counts := make(map[game.Card]int)
+
+	for _, card := range cards {
+		counts[card] += 1
+	}
+
+	return counts
+}
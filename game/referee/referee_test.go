@@ -0,0 +1,163 @@
+package referee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+func TestCheckDiscard(t *testing.T) {
+
+	cases := []struct {
+		Name      string
+		Hand      string
+		Card      string
+		Sequences []string
+		Flop      bool
+		Expected  []Kind
+	}{
+		{
+			Name:      "valid discard",
+			Hand:      "3-R:4-R:5-R:9-B",
+			Card:      "9-B",
+			Sequences: []string{"3-R:4-R:5-R"},
+			Flop:      true,
+			Expected:  []Kind{},
+		},
+		{
+			Name:      "discarded card not in hand",
+			Hand:      "3-R:4-R:5-R:9-B",
+			Card:      "10-B",
+			Sequences: []string{"3-R:4-R:5-R", "9-B"},
+			Expected:  []Kind{KindInventedCard},
+		},
+		{
+			Name:      "card missing from sequences",
+			Hand:      "3-R:4-R:5-R:9-B",
+			Card:      "9-B",
+			Sequences: []string{"3-R:4-R"},
+			Expected:  []Kind{KindMissingCard},
+		},
+		{
+			Name:      "card used twice",
+			Hand:      "3-R:4-R:5-R:9-B",
+			Card:      "9-B",
+			Sequences: []string{"3-R:4-R:5-R", "5-R"},
+			Expected:  []Kind{KindDuplicateCard},
+		},
+		{
+			Name:      "card invented in sequences",
+			Hand:      "3-R:4-R:5-R:9-B",
+			Card:      "9-B",
+			Sequences: []string{"3-R:4-R:5-R:6-R"},
+			Flop:      true,
+			Expected:  []Kind{KindInventedCard},
+		},
+		{
+			Name:      "false flop",
+			Hand:      "3-R:9-B:12-G:7-Y",
+			Card:      "7-Y",
+			Sequences: []string{"3-R:9-B:12-G"},
+			Flop:      true,
+			Expected:  []Kind{KindFalseFlop},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run("should check discard: "+tc.Name, func(t *testing.T) {
+			seqs := make([][]string, len(tc.Sequences))
+			for i, seq := range tc.Sequences {
+				seqs[i] = strings.Split(seq, ":")
+			}
+
+			violations := CheckDiscard(bots.BotRequest{
+				Action: bots.ActionDiscard,
+				Hand:   game.MustDecodeSequence(tc.Hand),
+				Round:  10,
+			}, bots.DiscardResponse{
+				Action:    bots.ActionDiscard,
+				Card:      tc.Card,
+				Sequences: seqs,
+				Flop:      tc.Flop,
+			})
+
+			kinds := make([]Kind, len(violations))
+			for i, v := range violations {
+				kinds[i] = v.Kind
+			}
+
+			assert.Equal(t, tc.Expected, kinds)
+		})
+	}
+}
+
+func TestCheckDraw(t *testing.T) {
+
+	violations := CheckDraw(bots.BotRequest{Action: bots.ActionDraw}, bots.DrawResponse{
+		Action: bots.ActionDraw,
+		Stack:  bots.StackDiscard,
+	})
+
+	require.Len(t, violations, 1)
+	assert.Equal(t, KindInvalidStack, violations[0].Kind)
+}
+
+// liarBot always claims to flop with a single sequence of its whole hand
+type liarBot struct{}
+
+func (liarBot) Draw(req bots.BotRequest) (bots.DrawResponse, error) {
+	return bots.DrawResponse{Action: bots.ActionDraw, Stack: bots.StackDeck}, nil
+}
+
+func (liarBot) Discard(req bots.BotRequest) (bots.DiscardResponse, error) {
+	return bots.DiscardResponse{Action: bots.ActionDiscard, Card: req.Hand[0].Encode(), Sequences: [][]string{game.EncodeCards(req.Hand[1:])}, Flop: true}, nil
+}
+
+func (liarBot) Score(req bots.BotRequest) (bots.ScoreResponse, error) {
+	return bots.ScoreResponse{Action: bots.ActionScore, Sequences: [][]string{game.EncodeCards(req.Hand)}, Flop: true}, nil
+}
+
+func TestRefereeModes(t *testing.T) {
+
+	req := bots.BotRequest{
+		Action: bots.ActionScore,
+		Hand:   game.MustDecodeSequence("3-R:9-B:12-G"),
+		Round:  10,
+	}
+
+	_, err := NewReferee(liarBot{}, ModeReject).Score(req)
+
+	var violationErr *ViolationError
+	require.ErrorAs(t, err, &violationErr)
+	assert.Equal(t, KindFalseFlop, violationErr.Violations[0].Kind)
+
+	flagged := 0
+	r := NewReferee(liarBot{}, ModeFlag)
+	r.OnViolation = func(err *ViolationError) {
+		flagged += 1
+	}
+
+	res, err := r.Score(req)
+
+	require.NoError(t, err)
+	assert.True(t, res.Flop)
+	assert.Equal(t, 1, flagged)
+}
+
+func TestRefereeAcceptsGrugbot(t *testing.T) {
+
+	r := NewReferee(grugbot.NewGrugBot(), ModeReject)
+
+	_, err := r.Discard(bots.BotRequest{
+		Action: bots.ActionDiscard,
+		Hand:   game.MustDecodeSequence("5-B:5-R:4-B:6-B:6-R:7-X:*:9-Y"),
+		Round:  7,
+	})
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,148 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RuleSet is a variant of the rules of five crowns
+// everything which deals, scores or validates cards has a method on RuleSet. the functions without one play the house rules
+
+type RuleSet struct {
+	Name string `json:"name"`
+	// points for a joker left in the hand
+	JokerValue int `json:"jokerValue"`
+	// points for the round's wild left in the hand. 0 scores it at its face value
+	WildValue int `json:"wildValue"`
+	// fewest cards in a run or set
+	MinSequenceLength int `json:"minSequenceLength"`
+	// whether a run can carry on from the king to the 3 e.g. Q-K-3
+	WrapRuns bool `json:"wrapRuns"`
+	// copies of each suited card in the deck
+	DeckCopies int `json:"deckCopies"`
+	DeckJokers int `json:"deckJokers"`
+	// the number of cards dealt in the first and last rounds, which is also the round's wild
+	FirstRound int `json:"firstRound"`
+	LastRound  int `json:"lastRound"`
+}
+
+var (
+	// the rules this repo has always played. jokers score 25 and wilds their face value
+	HouseRules = RuleSet{
+		Name:              "house",
+		JokerValue:        25,
+		MinSequenceLength: MinSequenceLength,
+		DeckCopies:        DeckCopies,
+		DeckJokers:        DeckJokers,
+		FirstRound:        3,
+		LastRound:         13,
+	}
+
+	// the published rules. jokers score 50 and wilds 20
+	OfficialRules = RuleSet{
+		Name:              "official",
+		JokerValue:        50,
+		WildValue:         20,
+		MinSequenceLength: MinSequenceLength,
+		DeckCopies:        DeckCopies,
+		DeckJokers:        DeckJokers,
+		FirstRound:        3,
+		LastRound:         13,
+	}
+
+	Presets = []RuleSet{HouseRules, OfficialRules}
+)
+
+// returns the preset with the given name
+func LookupRules(name string) (RuleSet, error) {
+	for _, rules := range Presets {
+		if rules.Name == name {
+			return rules, nil
+		}
+	}
+
+	return RuleSet{}, fmt.Errorf("unknown rules: %s", name)
+}
+
+// checks the rules can be played
+func (r RuleSet) Validate() error {
+	if r.FirstRound < 3 || r.LastRound > 13 || r.FirstRound > r.LastRound {
+		return fmt.Errorf("rounds must be between 3 and 13: %d to %d", r.FirstRound, r.LastRound)
+	} else if r.MinSequenceLength < 2 {
+		return fmt.Errorf("sequences must have at least 2 cards: %d", r.MinSequenceLength)
+	} else if r.DeckCopies < 1 || r.DeckJokers < 0 {
+		return errors.New("deck must have at least one copy of each card")
+	}
+
+	return nil
+}
+
+// returns the number of rounds in a match
+func (r RuleSet) Rounds() int {
+	return r.LastRound - r.FirstRound + 1
+}
+
+// returns the number of cards in a full deck
+func (r RuleSet) DeckSize() int {
+	return len(Suites())*(MaxNumber-MinNumber+1)*r.DeckCopies + r.DeckJokers
+}
+
+// creates a full, unshuffled deck
+func (r RuleSet) NewDeck() *Deck {
+	cards := make([]Card, 0, r.DeckSize())
+
+	for _, suite := range Suites() {
+		for number := MinNumber; number <= MaxNumber; number++ {
+			for range r.DeckCopies {
+				cards = append(cards, Card{
+					Number: number,
+					Suite:  suite,
+				})
+			}
+		}
+	}
+
+	for range r.DeckJokers {
+		cards = append(cards, CardJoker)
+	}
+
+	return &Deck{cards: cards}
+}
+
+// returns the points the card is worth when it is left in the hand at the end of the round
+func (r RuleSet) ScoreCard(card Card, round int) int {
+	if card.Joker {
+		return r.JokerValue
+	} else if card.Number == round && r.WildValue > 0 {
+		return r.WildValue
+	}
+
+	return card.Number
+}
+
+func (r RuleSet) ScoreSequence(cards []Card, round int) int {
+	score := 0
+	for _, card := range cards {
+		score += r.ScoreCard(card, round)
+	}
+
+	return score
+}
+
+// calculates the penalty for a hand arranged into sequences
+// cards which are not part of a valid run or set count towards the penalty
+func (r RuleSet) ScorePenalty(seqs [][]Card, round int) int {
+	score := 0
+	for _, seq := range seqs {
+		if r.ValidateSequence(seq, round) != nil {
+			score += r.ScoreSequence(seq, round)
+		}
+	}
+
+	return score
+}
+
+// determines if every sequence is a legal run or set, allowing the hand to go out
+func (r RuleSet) CanFlop(seqs [][]Card, round int) bool {
+	return r.ValidateSequences(seqs, round) == nil
+}
@@ -0,0 +1,170 @@
+package game
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesScoreCard(t *testing.T) {
+
+	cases := []struct {
+		Card     string
+		Round    int
+		House    int
+		Official int
+	}{
+		{Card: "*", Round: 3, House: 25, Official: 50},
+		{Card: "7-R", Round: 7, House: 7, Official: 20},
+		{Card: "7-R", Round: 8, House: 7, Official: 7},
+		{Card: "13-B", Round: 3, House: 13, Official: 13},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Card, func(t *testing.T) {
+			card, err := DecodeCard(tc.Card)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.House, HouseRules.ScoreCard(card, tc.Round))
+			assert.Equal(t, tc.Official, OfficialRules.ScoreCard(card, tc.Round))
+		})
+	}
+
+	// the functions without a rule set play the house rules
+	assert.Equal(t, 25, ScoreCard(CardJoker))
+}
+
+func TestRulesValidateSequence(t *testing.T) {
+
+	wrap := HouseRules
+	wrap.WrapRuns = true
+
+	long := HouseRules
+	long.MinSequenceLength = 4
+
+	cases := []struct {
+		Rules    RuleSet
+		Sequence string
+		Round    int
+		Expected error
+	}{
+		{Rules: HouseRules, Sequence: "12-R:13-R:3-R", Round: 10, Expected: ErrRunGap},
+		{Rules: wrap, Sequence: "12-R:13-R:3-R", Round: 10, Expected: nil},
+		{Rules: wrap, Sequence: "13-R:*:4-R", Round: 10, Expected: nil},
+		{Rules: wrap, Sequence: "11-R:13-R:4-R", Round: 10, Expected: ErrRunGap},
+		{Rules: long, Sequence: "3-R:4-R:5-R", Round: 10, Expected: ErrSequenceTooShort},
+		{Rules: long, Sequence: "3-R:4-R:5-R:*", Round: 10, Expected: nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Sequence, func(t *testing.T) {
+			seq, err := DecodeSequence(tc.Sequence)
+			require.NoError(t, err)
+
+			err = tc.Rules.ValidateSequence(seq, tc.Round)
+
+			if tc.Expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.Expected)
+			}
+		})
+	}
+}
+
+func TestRulesOptimalArrangement(t *testing.T) {
+
+	wrap := HouseRules
+	wrap.WrapRuns = true
+
+	cases := []struct {
+		Name     string
+		Rules    RuleSet
+		Hand     string
+		Round    int
+		Penalty  int
+		Leftover string
+	}{
+		{
+			Name:     "house wild",
+			Rules:    HouseRules,
+			Hand:     "9-R:9-B:9-Y:5-G",
+			Round:    5,
+			Penalty:  0,
+			Leftover: "",
+		},
+		{
+			// the spare wild joins the set, so it costs nothing under either rules
+			Name:     "official wild",
+			Rules:    OfficialRules,
+			Hand:     "9-R:9-B:9-Y:5-G",
+			Round:    5,
+			Penalty:  0,
+			Leftover: "",
+		},
+		{
+			Name:     "official joker",
+			Rules:    OfficialRules,
+			Hand:     "*:4-B",
+			Round:    5,
+			Penalty:  54,
+			Leftover: "4-B:*",
+		},
+		{
+			Name:     "wrapping run",
+			Rules:    wrap,
+			Hand:     "12-R:13-R:3-R:8-B",
+			Round:    5,
+			Penalty:  8,
+			Leftover: "8-B",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			hand, err := DecodeSequence(tc.Hand)
+			require.NoError(t, err)
+
+			arrangement := tc.Rules.OptimalArrangement(hand, tc.Round)
+
+			assert.Equal(t, tc.Penalty, arrangement.Penalty)
+			assert.Equal(t, tc.Penalty, tc.Rules.ScorePenalty(arrangement.Hand(), tc.Round))
+			leftover := slices.Clone(arrangement.Leftover)
+			slices.SortFunc(leftover, CompareCard)
+			assert.Equal(t, tc.Leftover, EncodeSequence(leftover))
+		})
+	}
+}
+
+func TestRulesDeck(t *testing.T) {
+
+	assert.Equal(t, 116, HouseRules.DeckSize())
+	assert.Equal(t, 116, HouseRules.NewDeck().Len())
+	assert.Equal(t, 11, OfficialRules.Rounds())
+
+	small := HouseRules
+	small.DeckCopies = 1
+	small.DeckJokers = 3
+
+	assert.Equal(t, 58, small.NewDeck().Len())
+}
+
+func TestLookupRules(t *testing.T) {
+
+	for _, preset := range Presets {
+		rules, err := LookupRules(preset.Name)
+
+		require.NoError(t, err)
+		assert.Equal(t, preset, rules)
+		assert.NoError(t, rules.Validate())
+	}
+
+	_, err := LookupRules("calvinball")
+	assert.Error(t, err)
+
+	invalid := HouseRules
+	invalid.LastRound = 14
+	assert.Error(t, invalid.Validate())
+}
@@ -1,18 +1,17 @@
 package game
 
+// scores the cards under the house rules
 func ScoreSequence(cards []Card) int {
-	score := 0
-	for _, card := range cards {
-		score += ScoreCard(card)
-	}
-
-	return score
+	return HouseRules.ScoreSequence(cards, 0)
 }
 
+// scores the card under the house rules, where a wild is worth its face value
 func ScoreCard(card Card) int {
-	if card.Joker {
-		return 25
-	} else {
-		return card.Number
-	}
+	return HouseRules.ScoreCard(card, 0)
+}
+
+// calculates the penalty for a hand arranged into sequences under the house rules
+// cards which are not part of a valid run or set count towards the penalty
+func ScorePenalty(seqs [][]Card, round int) int {
+	return HouseRules.ScorePenalty(seqs, round)
 }
@@ -0,0 +1,141 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+const (
+	// minimum number of cards required to make a run or set
+	MinSequenceLength = 3
+	// a run can at most span every number from 3 to 13
+	MaxRunLength = 11
+)
+
+var (
+	ErrSequenceTooShort   = errors.New("sequence has too few cards")
+	ErrSequenceMixed      = errors.New("cards are neither the same number nor the same suite")
+	ErrRunDuplicateNumber = errors.New("run contains the same number more than once")
+	ErrRunGap             = errors.New("run has more gaps than wilds to fill them")
+	ErrRunOutOfRange      = errors.New("run extends past 3 or 13")
+)
+
+// SequenceError describes which sequence in a hand is invalid and why
+type SequenceError struct {
+	Index    int
+	Sequence []Card
+	Err      error
+}
+
+func (e *SequenceError) Error() string {
+	return fmt.Sprintf("sequence %d (%s) is invalid: %s", e.Index, EncodeSequence(e.Sequence), e.Err)
+}
+
+func (e *SequenceError) Unwrap() error {
+	return e.Err
+}
+
+// checks that the sequence is a legal run or set under the house rules
+func ValidateSequence(seq []Card, round int) error {
+	return HouseRules.ValidateSequence(seq, round)
+}
+
+// checks that the sequence is a legal run or set
+// wilds may stand in for any card in either
+func (r RuleSet) ValidateSequence(seq []Card, round int) error {
+
+	if len(seq) < r.MinSequenceLength {
+		return ErrSequenceTooShort
+	}
+
+	naturals := make([]Card, 0, len(seq))
+	for _, card := range seq {
+		if !card.IsWild(round) {
+			naturals = append(naturals, card)
+		}
+	}
+
+	if len(naturals) <= 1 {
+		return nil
+	}
+
+	sameNumber := true
+	sameSuite := true
+	for _, card := range naturals[1:] {
+		sameNumber = sameNumber && card.Number == naturals[0].Number
+		sameSuite = sameSuite && card.Suite == naturals[0].Suite
+	}
+
+	if sameNumber {
+		// a set can use any number of cards as there are two of each card in the deck
+		return nil
+	} else if !sameSuite {
+		return ErrSequenceMixed
+	}
+
+	return r.validateRun(seq, naturals)
+}
+
+// checks that the natural cards in the run can be made consecutive with the available wilds
+func (r RuleSet) validateRun(seq []Card, naturals []Card) error {
+
+	if len(seq) > MaxRunLength {
+		return ErrRunOutOfRange
+	}
+
+	numbers := make([]int, len(naturals))
+	for i, card := range naturals {
+		numbers[i] = card.Number
+	}
+
+	slices.Sort(numbers)
+
+	if len(slices.Compact(slices.Clone(numbers))) != len(numbers) {
+		return ErrRunDuplicateNumber
+	}
+
+	wilds := len(seq) - len(naturals)
+
+	if r.span(numbers)-len(naturals) > wilds {
+		return ErrRunGap
+	}
+
+	return nil
+}
+
+// returns the length of the shortest run which includes every number. the numbers are sorted and distinct
+func (r RuleSet) span(numbers []int) int {
+	span := numbers[len(numbers)-1] - numbers[0] + 1
+
+	if r.WrapRuns {
+		// the run can instead start after any gap and carry on past the king
+		for i := 1; i < len(numbers); i++ {
+			span = min(span, MaxRunLength-(numbers[i]-numbers[i-1])+1)
+		}
+	}
+
+	return span
+}
+
+// validates every sequence under the house rules, returning a SequenceError for each invalid one
+func ValidateSequences(seqs [][]Card, round int) error {
+	return HouseRules.ValidateSequences(seqs, round)
+}
+
+// validates every sequence, returning a SequenceError for each invalid one
+func (r RuleSet) ValidateSequences(seqs [][]Card, round int) error {
+
+	var errs error
+	for i, seq := range seqs {
+		if err := r.ValidateSequence(seq, round); err != nil {
+			errs = errors.Join(errs, &SequenceError{
+				Index:    i,
+				Sequence: seq,
+				Err:      err,
+			})
+		}
+	}
+
+	return errs
+}
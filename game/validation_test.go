@@ -0,0 +1,69 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSequence(t *testing.T) {
+
+	cases := []struct {
+		Sequence string
+		Round    int
+		Expected error
+	}{
+		{Sequence: "3-R:4-R:5-R", Round: 10, Expected: nil},
+		{Sequence: "9-B:9-R:9-B:9-Y", Round: 10, Expected: nil},
+		{Sequence: "3-R:*:5-R", Round: 10, Expected: nil},
+		{Sequence: "3-R:5-R:10-G", Round: 10, Expected: nil},
+		{Sequence: "12-B:13-B:*:*", Round: 10, Expected: nil},
+		{Sequence: "*:*:*", Round: 10, Expected: nil},
+		{Sequence: "7-G:*", Round: 10, Expected: ErrSequenceTooShort},
+		{Sequence: "3-R:9-B:12-G", Round: 10, Expected: ErrSequenceMixed},
+		{Sequence: "3-R:3-R:4-R", Round: 10, Expected: ErrRunDuplicateNumber},
+		{Sequence: "3-R:9-R:*", Round: 10, Expected: ErrRunGap},
+		{Sequence: "3-R:4-R:5-R:6-R:7-R:8-R:9-R:10-R:11-R:12-R:13-R:*", Round: 10, Expected: ErrRunOutOfRange},
+	}
+
+	for _, tc := range cases {
+		t.Run("should validate sequence: "+tc.Sequence, func(t *testing.T) {
+			seq, err := DecodeSequence(tc.Sequence)
+
+			require.NoError(t, err)
+
+			err = ValidateSequence(seq, tc.Round)
+
+			if tc.Expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.Expected)
+			}
+		})
+	}
+}
+
+func TestValidateSequences(t *testing.T) {
+
+	seqs, err := DecodeSequences([]string{"3-R:4-R:5-R", "3-R:9-B:12-G"})
+
+	require.NoError(t, err)
+
+	err = ValidateSequences(seqs, 10)
+
+	var seqErr *SequenceError
+	require.ErrorAs(t, err, &seqErr)
+	assert.Equal(t, 1, seqErr.Index)
+	assert.ErrorIs(t, err, ErrSequenceMixed)
+	assert.False(t, CanFlop(seqs, 10))
+}
+
+func TestScorePenalty(t *testing.T) {
+
+	seqs, err := DecodeSequences([]string{"3-R:4-R:5-R", "3-R:9-B:12-G", "*"})
+
+	require.NoError(t, err)
+
+	assert.Equal(t, 49, ScorePenalty(seqs, 10))
+}
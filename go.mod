@@ -2,10 +2,19 @@ module github.com/timtatt/fivecrowns
 
 go 1.22.1
 
-require github.com/stretchr/testify v1.10.0
+require (
+	github.com/gorilla/websocket v1.5.3
+	github.com/stretchr/testify v1.10.0
+	google.golang.org/grpc v1.67.3
+	google.golang.org/protobuf v1.35.2
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.28.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
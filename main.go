@@ -1,21 +1,120 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
+	"io/fs"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/timtatt/fivecrowns/bots"
 	"github.com/timtatt/fivecrowns/bots/bigbrainbot"
+	"github.com/timtatt/fivecrowns/bots/galaxybrainbot"
+	"github.com/timtatt/fivecrowns/bots/grpcbot"
+	"github.com/timtatt/fivecrowns/bots/grpcbot/botpb"
 	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/bots/heuristicbot"
+	"github.com/timtatt/fivecrowns/bots/httpbot"
+	"github.com/timtatt/fivecrowns/bots/ismctsbot"
+	"github.com/timtatt/fivecrowns/bots/learnedbot"
+	"github.com/timtatt/fivecrowns/bots/montecarlobot"
 	"github.com/timtatt/fivecrowns/bots/smoothbrainbot"
+	"github.com/timtatt/fivecrowns/bots/wsbot"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/engine"
+	"github.com/timtatt/fivecrowns/game/referee"
+	"github.com/timtatt/fivecrowns/ratings"
+	"github.com/timtatt/fivecrowns/registry"
+	"github.com/timtatt/fivecrowns/replay"
+	"google.golang.org/grpc"
 )
 
+// commands which can be run in place of the server e.g `go run . selfplay -games 100`
+var commands = map[string]func(args []string) error{
+	"serve":       serve,
+	"selfplay":    selfPlay,
+	"train":       train,
+	"tune":        tune,
+	"conformance": conform,
+	"simulate":    simulate,
+	"compare":     compare,
+	"eval":        eval,
+}
+
 func main() {
 
-	port := *flag.String("port", "3000", "specify the port of the http server")
+	// the server runs when no command is given, so its flags can be passed straight through
+	command, args := "serve", os.Args[1:]
+
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	run, ok := commands[command]
+
+	if !ok {
+		log.Fatalf("unknown command: %s", command)
+	}
+
+	if err := run(args); err != nil {
+		log.Fatal(err)
+	}
+
+}
+
+func serve(args []string) error {
+
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	port := flags.String("port", "3000", "specify the port of the http server")
+	grpcPort := flags.String("grpc-port", "3001", "specify the port of the grpc server")
+	ratingsPath := flags.String("ratings", "ratings.json", "specify the file where bot ratings are kept")
+	matchesDir := flags.String("matches", "matches", "specify the directory where match logs are kept")
+	weightsPath := flags.String("weights", "", "specify a weights file for heuristicbot e.g. one written by the tune command")
+	registryPath := flags.String("registry", registry.DefaultConfig.Path, "specify the file where remote bot registrations are kept")
+	adminToken := flags.String("admin-token", "", "specify the bearer token required to register and deregister bots. bots cannot be registered without one")
+	healthInterval := flags.Duration("health-interval", 30*time.Second, "specify how often remote bots are pinged")
+	flags.Parse(args)
+
+	if *weightsPath != "" {
+		w, err := heuristicbot.LoadWeights(*weightsPath)
+
+		if err != nil {
+			return err
+		}
+
+		botFactories["heuristicbot"] = func() bots.Bot { return heuristicbot.NewHeuristicBot(w) }
+	}
+
+	// bots which dial in over websocket stay connected for the whole match
+	hub := wsbot.NewHub(wsbot.DefaultTimeout)
+
+	config := registry.DefaultConfig
+	config.Path = *registryPath
+	config.Wrap = refereed
+	config.Hub = hub
+
+	reg, err := registry.New(registeredBots(), config)
+
+	if err != nil {
+		return err
+	}
+
+	defer reg.Close()
+
+	// only bots registered with the ws transport can dial in, using the secret they were given
+	hub.Authorise = reg.AuthoriseWS
+
+	go reg.Watch(context.Background(), *healthInterval)
+	go serveGRPC(*grpcPort, reg)
 
 	slog.Info("starting web server")
 	mux := http.NewServeMux()
@@ -27,68 +126,266 @@ func main() {
 	fs := http.FileServer(http.Dir("./arena"))
 	mux.Handle("/arena/", http.StripPrefix("/arena/", fs))
 
-	configureBots(mux)
+	configureBots(mux, reg)
+	configureRegistry(mux, reg, *adminToken)
+	configureRatings(mux, *ratingsPath, reg)
+	configureMatches(mux, replay.NewStore(*matchesDir), reg, *ratingsPath, *adminToken)
 
-	slog.Info("listening on port " + port)
-	err := http.ListenAndServe(":"+port, mux)
+	mux.HandleFunc("GET /ws/bots/{name}", hub.Handler)
 
-	if err != nil {
-		log.Fatal(err)
+	slog.Info("listening on port " + *port)
+
+	return http.ListenAndServe(":"+*port, mux)
+}
+
+// creates each bot the server knows about
+// commands which play many matches at once need a fresh bot for every seat
+var botFactories = map[string]func() bots.Bot{
+	"smoothbrainbot": smoothbrainbot.NewSmoothBrainBot,
+	"grugbot":        grugbot.NewGrugBot,
+	"bigbrainbot":    bigbrainbot.NewBigBrainBot,
+	"galaxybrainbot": galaxybrainbot.NewGalaxyBrainBot,
+	"montecarlobot":  func() bots.Bot { return montecarlobot.NewMonteCarloBot(montecarlobot.DefaultConfig) },
+	"ismctsbot":      func() bots.Bot { return ismctsbot.NewISMCTSBot(ismctsbot.DefaultConfig) },
+	"learnedbot":     func() bots.Bot { return learnedbot.NewLearnedBot(learnedbot.DefaultModel()) },
+	"heuristicbot":   func() bots.Bot { return heuristicbot.NewHeuristicBot(heuristicbot.DefaultWeights) },
+}
+
+// returns every bot built into the server
+func registeredBots() map[string]bots.Bot {
+
+	b := make(map[string]bots.Bot, len(botFactories))
+
+	for botName, newBot := range botFactories {
+		b[botName] = refereed(botName, newBot())
 	}
 
+	return b
+}
+
+// responses which break the rules are flagged without interrupting the arena
+func refereed(botName string, bot bots.Bot) bots.Bot {
+	r := referee.NewReferee(bot, referee.ModeFlag)
+	r.OnViolation = func(err *referee.ViolationError) {
+		slog.Warn("bot returned an illegal response", "bot", botName, "err", err)
+	}
+
+	return r
+}
+
+// serves every bot in the registry, including bots registered after the server started
+func configureBots(mux *http.ServeMux, reg *registry.Registry) {
+
+	mux.HandleFunc("POST /bots/{name}", func(res http.ResponseWriter, req *http.Request) {
+		botName := req.PathValue("name")
+		bot, ok := reg.Bot(botName)
+
+		if !ok {
+			http.Error(res, "unknown bot: "+botName, http.StatusNotFound)
+			return
+		}
+
+		httpbot.NewHandler(botName, bot)(res, req)
+	})
+
+}
+
+// serves the leaderboard for every registered bot
+// the ratings are reloaded on each request so results from other runs show up straight away
+func configureRatings(mux *http.ServeMux, path string, reg *registry.Registry) {
+
+	mux.HandleFunc("GET /ratings", func(res http.ResponseWriter, req *http.Request) {
+		r, err := ratings.Load(path)
+
+		if err != nil {
+			slog.Error("unable to load ratings", "err", err)
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		for _, botName := range reg.Names() {
+			r.Register(botName)
+		}
+
+		writeJSON(res, r.Leaderboard())
+	})
+
 }
 
-func configureBots(mux *http.ServeMux) {
+type newMatchRequest struct {
+	Players []string `json:"players"`
+	Seed    uint64   `json:"seed"`
+	// name of the preset rules, the house rules when empty
+	Rules string `json:"rules,omitempty"`
+}
+
+// built in bots get a fresh bot for every seat, as bots like galaxybrainbot keep track of the match they are playing
+// remote bots are shared, the registry only keeps one connection to each
+func seatPlayers(reg *registry.Registry, botNames []string) ([]engine.Player, error) {
+	players := make([]engine.Player, len(botNames))
+
+	for i, botName := range botNames {
+		if newBot, ok := botFactories[botName]; ok {
+			players[i] = engine.Player{Name: botName, Bot: refereed(botName, newBot())}
+			continue
+		}
+
+		bot, ok := reg.Bot(botName)
 
-	b := map[string]bots.Bot{
-		"smoothbrainbot": smoothbrainbot.NewSmoothBrainBot(),
-		"grugbot":        grugbot.NewGrugBot(),
-		"bigbrainbot":    bigbrainbot.NewBigBrainBot(),
+		if !ok {
+			return nil, fmt.Errorf("unknown bot: %s", botName)
+		}
+
+		players[i] = engine.Player{Name: botName, Bot: bot}
 	}
 
-	for botName, bot := range b {
-		slog.Info("registering endpoint", "bot", botName)
-		mux.HandleFunc("POST /bots/"+botName, func(res http.ResponseWriter, req *http.Request) {
-			slog.Info("recieved request", "bot", botName)
+	return players, nil
+}
 
-			defer req.Body.Close()
+// serves recorded matches for the replay viewer
+// matches played to the end update the ratings saved at ratingsPath, but only when they are started with the admin token
+// so nobody else can pump the leaderboard
+func configureMatches(mux *http.ServeMux, store *replay.Store, reg *registry.Registry, ratingsPath string, adminToken string) {
 
-			var botReq bots.BotRequest
-			err := json.NewDecoder(req.Body).Decode(&botReq)
+	mux.HandleFunc("GET /api/matches", func(res http.ResponseWriter, req *http.Request) {
+		summaries, err := store.List()
 
-			if err != nil {
-				slog.Error("unable to unmarshal request", "err", err)
-				res.WriteHeader(http.StatusBadRequest)
-				return
-			}
+		if err != nil {
+			slog.Error("unable to list matches", "err", err)
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
 
-			var botRes interface{}
-			slog.Info("received request", "action", botReq.Action, "bot", botName, "req", botReq)
-			switch botReq.Action {
-			case bots.ActionScore:
-				botRes, err = bot.Score(botReq)
-			case bots.ActionDiscard:
-				botRes, err = bot.Discard(botReq)
-			case bots.ActionDraw:
-				botRes, err = bot.Draw(botReq)
-			}
-			slog.Info("calculated response", "action", botReq.Action, "bot", botName, "res", botRes)
+		writeJSON(res, summaries)
+	})
+
+	// plays a match between registered bots and records it
+	mux.HandleFunc("POST /api/matches", func(res http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+
+		var matchReq newMatchRequest
+		if err := json.NewDecoder(req.Body).Decode(&matchReq); err != nil {
+			slog.Error("unable to unmarshal request", "err", err)
+			res.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		players, err := seatPlayers(reg, matchReq.Players)
+
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		e := engine.NewEngine(players, matchReq.Seed)
+		e.Log = &engine.MatchLog{}
+
+		if matchReq.Rules != "" {
+			rules, err := game.LookupRules(matchReq.Rules)
 
 			if err != nil {
-				slog.Error("failed to get bot response", "err", err)
-				res.WriteHeader(http.StatusInternalServerError)
+				http.Error(res, err.Error(), http.StatusBadRequest)
 				return
 			}
 
-			err = json.NewEncoder(res).Encode(botRes)
+			e.Rules = rules
+		}
 
-			if err != nil {
-				slog.Error("failed to encode bot response", "err", err)
-				res.WriteHeader(http.StatusInternalServerError)
-				return
+		// a match which ends early is still saved so it can be reviewed, but is not rated
+		if result, err := e.Play(); err != nil {
+			slog.Warn("match ended early", "err", err)
+		} else if hasToken(adminToken, req) {
+			if err := ratings.Record(ratingsPath, result); err != nil {
+				slog.Error("unable to update ratings", "err", err)
 			}
+		}
+
+		id, err := store.Save(e.Log)
+
+		if err != nil {
+			slog.Error("unable to save match", "err", err)
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
 
-		})
+		writeJSON(res, replay.Summarise(id, e.Log))
+	})
+
+	mux.HandleFunc("GET /api/matches/{id}", func(res http.ResponseWriter, req *http.Request) {
+		matchLog, ok := loadMatch(res, store, req.PathValue("id"))
+
+		if ok {
+			writeJSON(res, matchLog)
+		}
+	})
+
+	mux.HandleFunc("GET /api/matches/{id}/frames", func(res http.ResponseWriter, req *http.Request) {
+		matchLog, ok := loadMatch(res, store, req.PathValue("id"))
+
+		if ok {
+			writeJSON(res, replay.Frames(matchLog))
+		}
+	})
+
+	mux.HandleFunc("GET /api/matches/{id}/frames/{frame}", func(res http.ResponseWriter, req *http.Request) {
+		matchLog, ok := loadMatch(res, store, req.PathValue("id"))
+
+		if !ok {
+			return
+		}
+
+		frames := replay.Frames(matchLog)
+		frame, err := strconv.Atoi(req.PathValue("frame"))
+
+		if err != nil || frame < 0 || frame >= len(frames) {
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		writeJSON(res, frames[frame])
+	})
+
+}
+
+func loadMatch(res http.ResponseWriter, store *replay.Store, id string) (*engine.MatchLog, bool) {
+	log, err := store.Load(id)
+
+	if errors.Is(err, replay.ErrInvalidID) || errors.Is(err, fs.ErrNotExist) {
+		res.WriteHeader(http.StatusNotFound)
+		return nil, false
+	} else if err != nil {
+		slog.Error("unable to load match", "id", id, "err", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return nil, false
 	}
 
+	return log, true
+}
+
+func writeJSON(res http.ResponseWriter, v any) {
+	res.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(res).Encode(v); err != nil {
+		slog.Error("failed to encode response", "err", err)
+		res.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func serveGRPC(port string, reg *registry.Registry) {
+
+	lis, err := net.Listen("tcp", ":"+port)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	botpb.RegisterBotServiceServer(server, grpcbot.NewLookupServer(reg.Bot))
+
+	slog.Info("listening for grpc on port " + port)
+	err = server.Serve(lis)
+
+	if err != nil {
+		log.Fatal(err)
+	}
 }
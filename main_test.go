@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/ratings"
+	"github.com/timtatt/fivecrowns/registry"
+	"github.com/timtatt/fivecrowns/replay"
+)
+
+// serves the match routes with every built in bot
+func newMatchServer(t *testing.T, ratingsPath string, adminToken string) *httptest.Server {
+	config := registry.DefaultConfig
+	config.Path = ""
+
+	reg, err := registry.New(registeredBots(), config)
+	require.NoError(t, err)
+	t.Cleanup(reg.Close)
+
+	mux := http.NewServeMux()
+	configureMatches(mux, replay.NewStore(t.TempDir()), reg, ratingsPath, adminToken)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestPlayMatchUpdatesRatings(t *testing.T) {
+
+	ratingsPath := filepath.Join(t.TempDir(), "ratings.json")
+	server := newMatchServer(t, ratingsPath, "secret")
+
+	match := `{"players":["grugbot","smoothbrainbot"],"seed":1}`
+
+	// anyone can play a match, but only matches started with the admin token are rated
+	require.Equal(t, http.StatusOK, adminRequest(t, http.MethodPost, server.URL+"/api/matches", match, ""))
+	require.Equal(t, http.StatusOK, adminRequest(t, http.MethodPost, server.URL+"/api/matches", match, "wrong"))
+
+	r, err := ratings.Load(ratingsPath)
+	require.NoError(t, err)
+	assert.Empty(t, r.Leaderboard())
+
+	require.Equal(t, http.StatusOK, adminRequest(t, http.MethodPost, server.URL+"/api/matches", match, "secret"))
+
+	r, err = ratings.Load(ratingsPath)
+	require.NoError(t, err)
+
+	grug := r.Get("grugbot")
+	smoothBrain := r.Get("smoothbrainbot")
+
+	assert.Equal(t, 1, grug.Matches)
+	assert.Equal(t, 1, smoothBrain.Matches)
+	assert.Greater(t, grug.Mu, ratings.DefaultMu)
+	assert.Less(t, smoothBrain.Mu, ratings.DefaultMu)
+}
+
+func TestPlayMatchWithoutTokenIsNotRated(t *testing.T) {
+
+	ratingsPath := filepath.Join(t.TempDir(), "ratings.json")
+	server := newMatchServer(t, ratingsPath, "")
+
+	require.Equal(t, http.StatusOK, adminRequest(t, http.MethodPost, server.URL+"/api/matches", `{"players":["grugbot","smoothbrainbot"],"seed":1}`, "secret"))
+
+	r, err := ratings.Load(ratingsPath)
+	require.NoError(t, err)
+	assert.Empty(t, r.Leaderboard())
+}
+
+func TestSeatPlayers(t *testing.T) {
+
+	config := registry.DefaultConfig
+	config.Path = ""
+
+	reg, err := registry.New(registeredBots(), config)
+	require.NoError(t, err)
+	defer reg.Close()
+
+	players, err := seatPlayers(reg, []string{"galaxybrainbot", "galaxybrainbot"})
+	require.NoError(t, err)
+
+	// the bot tracks the discard pile of its own match, so each seat needs its own bot
+	require.Len(t, players, 2)
+	assert.NotSame(t, players[0].Bot, players[1].Bot)
+
+	again, err := seatPlayers(reg, []string{"galaxybrainbot"})
+	require.NoError(t, err)
+	assert.NotSame(t, players[0].Bot, again[0].Bot)
+
+	_, err = seatPlayers(reg, []string{"galaxybrainbot", "nobot"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,269 @@
+package ratings
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"slices"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/timtatt/fivecrowns/game/engine"
+	"github.com/timtatt/fivecrowns/internal/atomicfile"
+)
+
+// Ratings keeps a skill estimate with uncertainty for every bot
+// uses the Weng-Lin Bradley-Terry model, which handles any number of players per match:
+// each match is treated as every pair of players playing each other, ranked by their final score
+
+const (
+	DefaultMu    = 25.0
+	DefaultSigma = DefaultMu / 3
+	beta         = DefaultSigma / 2
+	// stops sigma from shrinking to zero
+	kappa = 0.0001
+)
+
+type Rating struct {
+	Mu      float64 `json:"mu"`
+	Sigma   float64 `json:"sigma"`
+	Matches int     `json:"matches"`
+}
+
+func NewRating() Rating {
+	return Rating{
+		Mu:    DefaultMu,
+		Sigma: DefaultSigma,
+	}
+}
+
+// a pessimistic estimate of skill. the bot is 99% likely to be at least this strong
+func (r Rating) Conservative() float64 {
+	return r.Mu - 3*r.Sigma
+}
+
+type Ratings struct {
+	mu      sync.Mutex
+	players map[string]Rating
+}
+
+func New() *Ratings {
+	return &Ratings{
+		players: make(map[string]Rating),
+	}
+}
+
+// reads ratings from a json file. a missing file gives empty ratings
+func Load(path string) (*Ratings, error) {
+	r := New()
+
+	data, err := os.ReadFile(path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to read ratings: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &r.players); err != nil {
+		return nil, fmt.Errorf("unable to decode ratings: %w", err)
+	}
+
+	return r, nil
+}
+
+func (r *Ratings) Save(path string) error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.players, "", "  ")
+	r.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("unable to encode ratings: %w", err)
+	}
+
+	if err := atomicfile.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("unable to write ratings: %w", err)
+	}
+
+	return nil
+}
+
+// stops matches which finish at the same time from losing each other's updates
+var recordMu sync.Mutex
+
+// updates the ratings saved at path from every finished match
+func Record(path string, results ...engine.MatchResult) error {
+	recordMu.Lock()
+	defer recordMu.Unlock()
+
+	r, err := Load(path)
+
+	if err != nil {
+		return err
+	}
+
+	for _, res := range results {
+		r.UpdateMatch(res)
+	}
+
+	return r.Save(path)
+}
+
+// adds a player with the default rating if they have not been rated yet
+func (r *Ratings) Register(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.players[name]; !ok {
+		r.players[name] = NewRating()
+	}
+}
+
+func (r *Ratings) Get(name string) Rating {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rating, ok := r.players[name]
+
+	if !ok {
+		return NewRating()
+	}
+
+	return rating
+}
+
+func (r *Ratings) UpdateMatch(res engine.MatchResult) {
+	r.Update(res.Players, res.Totals)
+}
+
+// updates the ratings of every player in a match from their final scores, lowest score being best
+// a player in more than one seat is rated once on its mean score, so it is never compared against itself
+func (r *Ratings) Update(names []string, seatScores []int) {
+	names, scores := merge(names, seatScores)
+
+	// a player on its own learns nothing
+	if len(names) < 2 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := make([]Rating, len(names))
+	for i, name := range names {
+		rating, ok := r.players[name]
+
+		if !ok {
+			rating = NewRating()
+		}
+
+		before[i] = rating
+	}
+
+	for i, name := range names {
+		omega := 0.0
+		delta := 0.0
+
+		for q := range names {
+			if q == i {
+				continue
+			}
+
+			c := math.Sqrt(before[i].Sigma*before[i].Sigma + before[q].Sigma*before[q].Sigma + 2*beta*beta)
+			p := 1 / (1 + math.Exp((before[q].Mu-before[i].Mu)/c))
+
+			// the outcome from player i's point of view
+			s := 0.5
+			if scores[i] < scores[q] {
+				s = 1
+			} else if scores[i] > scores[q] {
+				s = 0
+			}
+
+			variance := before[i].Sigma * before[i].Sigma
+			gamma := before[i].Sigma / c
+
+			omega += variance / c * (s - p)
+			delta += gamma * variance / (c * c) * p * (1 - p)
+		}
+
+		r.players[name] = Rating{
+			Mu:      before[i].Mu + omega,
+			Sigma:   before[i].Sigma * math.Sqrt(math.Max(1-delta, kappa)),
+			Matches: before[i].Matches + 1,
+		}
+	}
+}
+
+// returns each name once, with its mean score across its seats
+func merge(names []string, scores []int) ([]string, []float64) {
+	out := make([]string, 0, len(names))
+	totals := make([]float64, 0, len(names))
+	seats := make([]int, 0, len(names))
+
+	for i, name := range names {
+		idx := slices.Index(out, name)
+
+		if idx < 0 {
+			out = append(out, name)
+			totals = append(totals, 0)
+			seats = append(seats, 0)
+			idx = len(out) - 1
+		}
+
+		totals[idx] += float64(scores[i])
+		seats[idx] += 1
+	}
+
+	for i := range totals {
+		totals[i] /= float64(seats[i])
+	}
+
+	return out, totals
+}
+
+type Entry struct {
+	Name string `json:"name"`
+	Rating
+}
+
+// returns every player ordered by their conservative rating
+func (r *Ratings) Leaderboard() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]Entry, 0, len(r.players))
+	for name, rating := range r.players {
+		entries = append(entries, Entry{
+			Name:   name,
+			Rating: rating,
+		})
+	}
+
+	slices.SortFunc(entries, func(a, b Entry) int {
+		if c := cmp.Compare(b.Conservative(), a.Conservative()); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return entries
+}
+
+func WriteLeaderboard(w io.Writer, entries []Entry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "#\tbot\trating\tmu\tsigma\tmatches")
+
+	for i, e := range entries {
+		fmt.Fprintf(tw, "%d\t%s\t%.2f\t%.2f\t%.2f\t%d\n", i+1, e.Name, e.Conservative(), e.Mu, e.Sigma, e.Matches)
+	}
+
+	return tw.Flush()
+}
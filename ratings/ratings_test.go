@@ -0,0 +1,110 @@
+package ratings
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/game/engine"
+)
+
+func TestUpdate(t *testing.T) {
+
+	r := New()
+
+	r.Update([]string{"grugbot", "bigbrainbot", "smoothbrainbot"}, []int{40, 60, 600})
+
+	grug := r.Get("grugbot")
+	bigBrain := r.Get("bigbrainbot")
+	smoothBrain := r.Get("smoothbrainbot")
+
+	assert.Greater(t, grug.Mu, bigBrain.Mu)
+	assert.Greater(t, bigBrain.Mu, smoothBrain.Mu)
+	assert.Less(t, grug.Sigma, DefaultSigma)
+	assert.Equal(t, 1, grug.Matches)
+}
+
+func TestUpdateTie(t *testing.T) {
+
+	r := New()
+
+	r.Update([]string{"grugbot", "bigbrainbot"}, []int{40, 40})
+
+	assert.InDelta(t, DefaultMu, r.Get("grugbot").Mu, 1e-9)
+	assert.InDelta(t, DefaultMu, r.Get("bigbrainbot").Mu, 1e-9)
+}
+
+func TestLeaderboard(t *testing.T) {
+
+	r := New()
+	r.Register("smoothbrainbot")
+
+	for range 20 {
+		r.Update([]string{"grugbot", "smoothbrainbot"}, []int{40, 600})
+	}
+
+	leaderboard := r.Leaderboard()
+
+	require.Len(t, leaderboard, 2)
+	assert.Equal(t, "grugbot", leaderboard[0].Name)
+	assert.Equal(t, 20, leaderboard[0].Matches)
+}
+
+func TestSaveLoad(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "ratings.json")
+
+	r, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, r.Leaderboard())
+
+	r.Update([]string{"grugbot", "bigbrainbot"}, []int{40, 60})
+	require.NoError(t, r.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, r.Leaderboard(), loaded.Leaderboard())
+}
+
+func TestRecord(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "ratings.json")
+
+	require.NoError(t, Record(path, engine.MatchResult{
+		Players: []string{"grugbot", "smoothbrainbot"},
+		Totals:  []int{40, 600},
+	}))
+	require.NoError(t, Record(path, engine.MatchResult{
+		Players: []string{"grugbot", "bigbrainbot"},
+		Totals:  []int{40, 60},
+	}))
+
+	r, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, r.Get("grugbot").Matches)
+	assert.Greater(t, r.Get("grugbot").Mu, r.Get("bigbrainbot").Mu)
+	assert.Equal(t, 1, r.Get("smoothbrainbot").Matches)
+}
+
+func TestUpdateSameBotTwice(t *testing.T) {
+
+	r := New()
+
+	// a mirror match says nothing about the bot
+	r.Update([]string{"grugbot", "grugbot"}, []int{40, 60})
+
+	assert.Equal(t, NewRating(), r.Get("grugbot"))
+	assert.Empty(t, r.Leaderboard())
+
+	// each bot is rated once, grugbot on its mean score of 50
+	r.Update([]string{"grugbot", "bigbrainbot", "grugbot"}, []int{40, 45, 60})
+
+	grug := r.Get("grugbot")
+	bigBrain := r.Get("bigbrainbot")
+
+	assert.Equal(t, 1, grug.Matches)
+	assert.Less(t, grug.Mu, DefaultMu)
+	assert.Greater(t, bigBrain.Mu, DefaultMu)
+	assert.InDelta(t, DefaultMu-grug.Mu, bigBrain.Mu-DefaultMu, 1e-9)
+}
@@ -0,0 +1,522 @@
+package registry
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grpcbot"
+	"github.com/timtatt/fivecrowns/bots/httpbot"
+	"github.com/timtatt/fivecrowns/bots/wsbot"
+	"github.com/timtatt/fivecrowns/internal/atomicfile"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Registry keeps every bot the server plays: the bots built into the server and remote bots registered while it runs
+// remote registrations are saved to a json file so they survive restarts
+
+type Transport string
+
+const (
+	// the bot implements the HTTP spec in the README at its url
+	TransportHTTP Transport = "http"
+	// the bot implements the BotService at its url e.g. localhost:3001
+	TransportGRPC Transport = "grpc"
+	// the bot dials in to /ws/bots/{name}, so it has no url
+	TransportWS Transport = "ws"
+)
+
+var (
+	ErrExists   = errors.New("bot is already registered")
+	ErrNotFound = errors.New("bot is not registered")
+	ErrBuiltIn  = errors.New("bot is built into the server")
+	ErrInvalid  = errors.New("invalid registration")
+)
+
+type Registration struct {
+	Name         string    `json:"name"`
+	Transport    Transport `json:"transport"`
+	URL          string    `json:"url,omitempty"`
+	Owner        string    `json:"owner"`
+	Description  string    `json:"description,omitempty"`
+	RegisteredAt time.Time `json:"registeredAt"`
+	// ws bots send the secret when they connect, so nobody else can play as them
+	// it is made when the bot is registered and only returned then
+	Secret string `json:"secret,omitempty"`
+}
+
+// the result of the latest health check
+type Health struct {
+	Healthy   bool      `json:"healthy"`
+	CheckedAt time.Time `json:"checkedAt"`
+	Error     string    `json:"error,omitempty"`
+}
+
+type Status struct {
+	Name    string `json:"name"`
+	BuiltIn bool   `json:"builtIn"`
+	// not set for built in bots
+	*Registration
+	Health *Health `json:"health,omitempty"`
+}
+
+type Config struct {
+	// file the registrations are saved to. registrations are not saved when empty
+	Path string
+	// wraps every remote bot before it plays e.g. with a referee
+	Wrap func(name string, bot bots.Bot) bots.Bot
+	// connections of bots registered with the ws transport
+	Hub *wsbot.Hub
+	// used for bots registered with the http transport
+	HTTP httpbot.Config
+	// timeout for each grpc request and health check
+	Timeout time.Duration
+}
+
+var DefaultConfig = Config{
+	Path:    "bots.json",
+	HTTP:    httpbot.DefaultConfig,
+	Timeout: 5 * time.Second,
+}
+
+type remote struct {
+	registration Registration
+	bot          bots.Bot
+	// only set for grpc bots
+	conn   *grpc.ClientConn
+	health *Health
+}
+
+type Registry struct {
+	config  Config
+	builtIn map[string]bots.Bot
+	client  *http.Client
+
+	mu      sync.RWMutex
+	remotes map[string]*remote
+}
+
+// creates a registry with the built in bots and every registration saved to the config's path
+func New(builtIn map[string]bots.Bot, config Config) (*Registry, error) {
+	r := &Registry{
+		config:  config,
+		builtIn: builtIn,
+		client: &http.Client{
+			Timeout: config.Timeout,
+		},
+		remotes: make(map[string]*remote),
+	}
+
+	if config.Path == "" {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(config.Path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to read registrations: %w", err)
+	}
+
+	registrations := make([]Registration, 0)
+	if err := json.Unmarshal(data, &registrations); err != nil {
+		return nil, fmt.Errorf("unable to decode registrations: %w", err)
+	}
+
+	for _, registration := range registrations {
+		rem, err := r.connect(registration)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to restore %s: %w", registration.Name, err)
+		}
+
+		r.remotes[registration.Name] = rem
+	}
+
+	return r, nil
+}
+
+// returns the bot with the given name, built in or remote
+func (r *Registry) Bot(name string) (bots.Bot, bool) {
+	if bot, ok := r.builtIn[name]; ok {
+		return bot, true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rem, ok := r.remotes[name]
+
+	if !ok {
+		return nil, false
+	}
+
+	return rem.bot, true
+}
+
+// returns the names of every bot in alphabetical order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.builtIn)+len(r.remotes))
+	for name := range r.builtIn {
+		names = append(names, name)
+	}
+	for name := range r.remotes {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// returns every bot with its registration and latest health check, in alphabetical order
+func (r *Registry) List() []Status {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(r.builtIn)+len(r.remotes))
+	for name := range r.builtIn {
+		statuses = append(statuses, Status{
+			Name:    name,
+			BuiltIn: true,
+		})
+	}
+
+	for _, rem := range r.remotes {
+		registration := rem.registration
+		registration.Secret = ""
+
+		statuses = append(statuses, Status{
+			Name:         registration.Name,
+			Registration: &registration,
+			Health:       rem.health,
+		})
+	}
+
+	slices.SortFunc(statuses, func(a, b Status) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return statuses
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func validate(registration Registration) error {
+	if !validName.MatchString(registration.Name) {
+		return fmt.Errorf("%w: name must be lowercase letters, numbers, dashes and underscores: %q", ErrInvalid, registration.Name)
+	} else if registration.Owner == "" {
+		return fmt.Errorf("%w: owner is required", ErrInvalid)
+	}
+
+	switch registration.Transport {
+	case TransportHTTP:
+		u, err := url.Parse(registration.URL)
+
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: url must be an http url: %q", ErrInvalid, registration.URL)
+		}
+	case TransportGRPC:
+		if registration.URL == "" {
+			return fmt.Errorf("%w: url is required", ErrInvalid)
+		}
+	case TransportWS:
+	default:
+		return fmt.Errorf("%w: unknown transport: %q", ErrInvalid, registration.Transport)
+	}
+
+	return nil
+}
+
+// adds a remote bot and saves the registrations
+func (r *Registry) Register(registration Registration) (Status, error) {
+	if err := validate(registration); err != nil {
+		return Status{}, err
+	}
+
+	if _, ok := r.builtIn[registration.Name]; ok {
+		return Status{}, fmt.Errorf("%w: %s", ErrBuiltIn, registration.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.remotes[registration.Name]; ok {
+		return Status{}, fmt.Errorf("%w: %s", ErrExists, registration.Name)
+	}
+
+	registration.RegisteredAt = time.Now().UTC()
+	registration.Secret = ""
+
+	if registration.Transport == TransportWS {
+		secret, err := newSecret()
+
+		if err != nil {
+			return Status{}, err
+		}
+
+		registration.Secret = secret
+	}
+
+	rem, err := r.connect(registration)
+
+	if err != nil {
+		return Status{}, err
+	}
+
+	r.remotes[registration.Name] = rem
+
+	if err := r.save(); err != nil {
+		delete(r.remotes, registration.Name)
+		rem.close()
+
+		return Status{}, err
+	}
+
+	slog.Info("registered bot", "bot", registration.Name, "transport", registration.Transport, "owner", registration.Owner)
+
+	return Status{Name: registration.Name, Registration: &registration}, nil
+}
+
+// checks a bot connecting over websocket is registered with the ws transport and sent its secret
+func (r *Registry) AuthoriseWS(name string, secret string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rem, ok := r.remotes[name]
+
+	if !ok || rem.registration.Transport != TransportWS || rem.registration.Secret == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(rem.registration.Secret)) == 1
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("unable to make secret: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// removes a remote bot and saves the registrations
+func (r *Registry) Deregister(name string) error {
+	if _, ok := r.builtIn[name]; ok {
+		return fmt.Errorf("%w: %s", ErrBuiltIn, name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rem, ok := r.remotes[name]
+
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	delete(r.remotes, name)
+
+	if err := r.save(); err != nil {
+		r.remotes[name] = rem
+		return err
+	}
+
+	rem.close()
+
+	slog.Info("deregistered bot", "bot", name)
+
+	return nil
+}
+
+// closes the connections to every remote bot
+func (r *Registry) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rem := range r.remotes {
+		rem.close()
+	}
+}
+
+func (r *Registry) connect(registration Registration) (*remote, error) {
+	rem := &remote{registration: registration}
+
+	switch registration.Transport {
+	case TransportHTTP:
+		rem.bot = httpbot.NewHTTPBot(registration.URL, r.config.HTTP)
+	case TransportGRPC:
+		conn, err := grpc.NewClient(registration.URL, grpc.WithTransportCredentials(insecure.NewCredentials()))
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to create grpc client: %w", err)
+		}
+
+		rem.conn = conn
+		rem.bot = grpcbot.NewGRPCBot(conn, registration.Name, r.config.Timeout)
+	case TransportWS:
+		if r.config.Hub == nil {
+			return nil, fmt.Errorf("%w: the server does not accept websocket bots", ErrInvalid)
+		}
+
+		rem.bot = r.config.Hub.Remote(registration.Name)
+	default:
+		return nil, fmt.Errorf("%w: unknown transport: %q", ErrInvalid, registration.Transport)
+	}
+
+	if r.config.Wrap != nil {
+		rem.bot = r.config.Wrap(registration.Name, rem.bot)
+	}
+
+	return rem, nil
+}
+
+func (rem *remote) close() {
+	if rem.conn != nil {
+		rem.conn.Close()
+	}
+}
+
+// must be called with the lock held
+func (r *Registry) save() error {
+	if r.config.Path == "" {
+		return nil
+	}
+
+	registrations := make([]Registration, 0, len(r.remotes))
+	for _, rem := range r.remotes {
+		registrations = append(registrations, rem.registration)
+	}
+
+	slices.SortFunc(registrations, func(a, b Registration) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	data, err := json.MarshalIndent(registrations, "", "  ")
+
+	if err != nil {
+		return fmt.Errorf("unable to encode registrations: %w", err)
+	}
+
+	if err := atomicfile.WriteFile(r.config.Path, data, 0o600); err != nil {
+		return fmt.Errorf("unable to write registrations: %w", err)
+	}
+
+	return nil
+}
+
+// checks every remote bot is reachable and records the result
+// http bots are sent GET /ping on the host of their url, grpc bots must connect and ws bots must be connected
+func (r *Registry) Check(ctx context.Context) {
+	r.mu.RLock()
+	remotes := make([]*remote, 0, len(r.remotes))
+	for _, rem := range r.remotes {
+		remotes = append(remotes, rem)
+	}
+	r.mu.RUnlock()
+
+	for _, rem := range remotes {
+		err := r.check(ctx, rem)
+
+		health := &Health{
+			Healthy:   err == nil,
+			CheckedAt: time.Now().UTC(),
+		}
+
+		if err != nil {
+			health.Error = err.Error()
+			slog.Warn("bot failed health check", "bot", rem.registration.Name, "err", err)
+		}
+
+		r.mu.Lock()
+		rem.health = health
+		r.mu.Unlock()
+	}
+}
+
+func (r *Registry) check(ctx context.Context, rem *remote) error {
+	ctx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+	defer cancel()
+
+	switch rem.registration.Transport {
+	case TransportHTTP:
+		u, err := url.Parse(rem.registration.URL)
+
+		if err != nil {
+			return fmt.Errorf("unable to parse url: %w", err)
+		}
+
+		ping := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/ping"}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ping.String(), nil)
+
+		if err != nil {
+			return fmt.Errorf("unable to create ping request: %w", err)
+		}
+
+		res, err := r.client.Do(req)
+
+		if err != nil {
+			return fmt.Errorf("unable to ping bot: %w", err)
+		}
+
+		res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("ping returned status %d", res.StatusCode)
+		}
+	case TransportGRPC:
+		rem.conn.Connect()
+
+		for state := rem.conn.GetState(); state != connectivity.Ready; state = rem.conn.GetState() {
+			if !rem.conn.WaitForStateChange(ctx, state) {
+				return fmt.Errorf("unable to connect to bot: %s", state)
+			}
+		}
+	case TransportWS:
+		if _, ok := r.config.Hub.Bot(rem.registration.Name); !ok {
+			return errors.New("bot is not connected")
+		}
+	}
+
+	return nil
+}
+
+// checks the remote bots every interval until the context is done
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/bots/httpbot"
+	"github.com/timtatt/fivecrowns/bots/wsbot"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+// serves grugbot with the same routes as the arena
+func newServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ping", func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("pong"))
+	})
+	mux.HandleFunc("POST /bots/grugbot", httpbot.NewHandler("grugbot", grugbot.NewGrugBot()))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newConfig(t *testing.T) Config {
+	config := DefaultConfig
+	config.Path = filepath.Join(t.TempDir(), "bots.json")
+	config.Hub = wsbot.NewHub(time.Second)
+	config.Timeout = time.Second
+
+	return config
+}
+
+var builtIn = map[string]bots.Bot{
+	"grugbot": grugbot.NewGrugBot(),
+}
+
+func TestRegistry(t *testing.T) {
+
+	server := newServer(t)
+	config := newConfig(t)
+
+	r, err := New(builtIn, config)
+	require.NoError(t, err)
+
+	_, err = r.Register(Registration{
+		Name:        "remote",
+		Transport:   TransportHTTP,
+		URL:         server.URL + "/bots/grugbot",
+		Owner:       "tim",
+		Description: "grugbot over http",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"grugbot", "remote"}, r.Names())
+
+	// the registrations are written to a temporary file which replaces bots.json
+	entries, err := os.ReadDir(filepath.Dir(config.Path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "bots.json", entries[0].Name())
+
+	bot, ok := r.Bot("remote")
+	require.True(t, ok)
+
+	res, err := bot.Draw(bots.BotRequest{
+		Hand:    game.MustDecodeSequence("9-R:10-R:5-X"),
+		Round:   3,
+		Discard: game.MustDecodeSequence("11-R"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, bots.ActionDraw, res.Action)
+
+	// registrations survive a restart
+	restarted, err := New(builtIn, config)
+	require.NoError(t, err)
+
+	statuses := restarted.List()
+	require.Len(t, statuses, 2)
+	assert.True(t, statuses[0].BuiltIn)
+	assert.Equal(t, "tim", statuses[1].Owner)
+	assert.Equal(t, "grugbot over http", statuses[1].Description)
+
+	require.NoError(t, restarted.Deregister("remote"))
+
+	_, ok = restarted.Bot("remote")
+	assert.False(t, ok)
+
+	again, err := New(builtIn, config)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"grugbot"}, again.Names())
+}
+
+func TestRegistryErrors(t *testing.T) {
+
+	r, err := New(builtIn, newConfig(t))
+	require.NoError(t, err)
+
+	_, err = r.Register(Registration{Name: "remote", Transport: TransportWS, Owner: "tim"})
+	require.NoError(t, err)
+
+	cases := []struct {
+		Name         string
+		Registration Registration
+		Expected     error
+	}{
+		{
+			Name:         "built in",
+			Registration: Registration{Name: "grugbot", Transport: TransportWS, Owner: "tim"},
+			Expected:     ErrBuiltIn,
+		},
+		{
+			Name:         "registered",
+			Registration: Registration{Name: "remote", Transport: TransportWS, Owner: "tim"},
+			Expected:     ErrExists,
+		},
+		{
+			Name:         "invalid name",
+			Registration: Registration{Name: "../remote", Transport: TransportWS, Owner: "tim"},
+			Expected:     ErrInvalid,
+		},
+		{
+			Name:         "no owner",
+			Registration: Registration{Name: "other", Transport: TransportWS},
+			Expected:     ErrInvalid,
+		},
+		{
+			Name:         "unknown transport",
+			Registration: Registration{Name: "other", Transport: "carrier-pigeon", Owner: "tim"},
+			Expected:     ErrInvalid,
+		},
+		{
+			Name:         "http without a url",
+			Registration: Registration{Name: "other", Transport: TransportHTTP, Owner: "tim"},
+			Expected:     ErrInvalid,
+		},
+		{
+			Name:         "grpc without a url",
+			Registration: Registration{Name: "other", Transport: TransportGRPC, Owner: "tim"},
+			Expected:     ErrInvalid,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			_, err := r.Register(c.Registration)
+			assert.ErrorIs(t, err, c.Expected)
+		})
+	}
+
+	assert.ErrorIs(t, r.Deregister("grugbot"), ErrBuiltIn)
+	assert.ErrorIs(t, r.Deregister("missing"), ErrNotFound)
+}
+
+func TestRegistryCheck(t *testing.T) {
+
+	server := newServer(t)
+
+	r, err := New(builtIn, newConfig(t))
+	require.NoError(t, err)
+
+	registrations := []Registration{
+		{Name: "http", Transport: TransportHTTP, URL: server.URL + "/bots/grugbot", Owner: "tim"},
+		{Name: "offline", Transport: TransportHTTP, URL: "http://127.0.0.1:1/bots/grugbot", Owner: "tim"},
+		{Name: "ws", Transport: TransportWS, Owner: "tim"},
+	}
+
+	for _, registration := range registrations {
+		_, err := r.Register(registration)
+		require.NoError(t, err)
+	}
+
+	r.Check(context.Background())
+
+	health := make(map[string]bool)
+	for _, status := range r.List() {
+		if status.Health != nil {
+			health[status.Name] = status.Health.Healthy
+		}
+	}
+
+	// the websocket bot has not dialled in
+	assert.Equal(t, map[string]bool{"http": true, "offline": false, "ws": false}, health)
+}
+
+func TestRegistryWSSecret(t *testing.T) {
+
+	server := newServer(t)
+	config := newConfig(t)
+
+	r, err := New(builtIn, config)
+	require.NoError(t, err)
+
+	// a secret sent by the admin is replaced
+	status, err := r.Register(Registration{Name: "ws", Transport: TransportWS, Owner: "tim", Secret: "guessable"})
+	require.NoError(t, err)
+
+	secret := status.Secret
+	require.NotEmpty(t, secret)
+	assert.NotEqual(t, "guessable", secret)
+
+	_, err = r.Register(Registration{Name: "http", Transport: TransportHTTP, URL: server.URL + "/bots/grugbot", Owner: "tim"})
+	require.NoError(t, err)
+
+	assert.True(t, r.AuthoriseWS("ws", secret))
+	assert.False(t, r.AuthoriseWS("ws", "guessable"))
+	assert.False(t, r.AuthoriseWS("ws", ""))
+	assert.False(t, r.AuthoriseWS("http", ""))
+	assert.False(t, r.AuthoriseWS("grugbot", ""))
+	assert.False(t, r.AuthoriseWS("unregistered", secret))
+
+	// the secret is never listed
+	for _, status := range r.List() {
+		if status.Registration != nil {
+			assert.Empty(t, status.Secret)
+		}
+	}
+
+	// only the server can read the saved secrets
+	info, err := os.Stat(config.Path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// and survives a restart
+	restarted, err := New(builtIn, config)
+	require.NoError(t, err)
+	assert.True(t, restarted.AuthoriseWS("ws", secret))
+}
@@ -0,0 +1,99 @@
+package replay
+
+import (
+	"slices"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/engine"
+)
+
+// Frame is the state of the table at the end of a turn
+
+type Frame struct {
+	Round int `json:"round"`
+	Turn  int `json:"turn"`
+	// the player who took the turn
+	Seat       int        `json:"seat"`
+	Stack      bots.Stack `json:"stack"`
+	NewestCard string     `json:"newestCard"`
+	Discarded  string     `json:"discarded"`
+	// top-most card is at index 0
+	Discard []string      `json:"discard"`
+	Players []PlayerFrame `json:"players"`
+}
+
+type PlayerFrame struct {
+	Name string   `json:"name"`
+	Hand []string `json:"hand"`
+	// the sequences from the player's latest discard in the round
+	Sequences [][]string `json:"sequences"`
+	Flop      bool       `json:"flop"`
+}
+
+// rebuilds the table turn by turn from the requests and responses in the log
+func Frames(log *engine.MatchLog) []Frame {
+	frames := make([]Frame, 0)
+
+	players := make([]PlayerFrame, len(log.Players))
+	round := 0
+	var stack bots.Stack
+
+	for i, event := range log.Events {
+
+		if event.Round != round {
+			round = event.Round
+			players = dealtHands(log, i)
+		}
+
+		if event.Draw != nil {
+			stack = event.Draw.Stack
+		}
+
+		if event.Discard == nil {
+			continue
+		}
+
+		hand := game.EncodeCards(event.Request.Hand)
+		if idx := slices.Index(hand, event.Discard.Card); idx != -1 {
+			hand = slices.Delete(hand, idx, idx+1)
+		}
+
+		players[event.Seat].Hand = hand
+		players[event.Seat].Sequences = event.Discard.Sequences
+		players[event.Seat].Flop = event.Discard.Flop
+
+		frames = append(frames, Frame{
+			Round:      event.Round,
+			Turn:       event.Turn,
+			Seat:       event.Seat,
+			Stack:      stack,
+			NewestCard: event.Request.NewestCard.Encode(),
+			Discarded:  event.Discard.Card,
+			Discard:    append([]string{event.Discard.Card}, game.EncodeCards(event.Request.Discard)...),
+			Players:    slices.Clone(players),
+		})
+	}
+
+	return frames
+}
+
+// finds the hand each player was dealt from their first request in the round starting at the given event
+func dealtHands(log *engine.MatchLog, start int) []PlayerFrame {
+	players := make([]PlayerFrame, len(log.Players))
+	for i, name := range log.Players {
+		players[i].Name = name
+	}
+
+	for _, event := range log.Events[start:] {
+		if event.Round != log.Events[start].Round {
+			break
+		}
+
+		if event.Draw != nil && players[event.Seat].Hand == nil {
+			players[event.Seat].Hand = game.EncodeCards(event.Request.Hand)
+		}
+	}
+
+	return players
+}
@@ -0,0 +1,109 @@
+package replay
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots/bigbrainbot"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/game/engine"
+)
+
+func recordMatch(t *testing.T) *engine.MatchLog {
+	e := engine.NewEngine([]engine.Player{
+		{Name: "grugbot", Bot: grugbot.NewGrugBot()},
+		{Name: "bigbrainbot", Bot: bigbrainbot.NewBigBrainBot()},
+	}, 3)
+	e.Log = &engine.MatchLog{}
+
+	_, err := e.Play()
+	require.NoError(t, err)
+
+	return e.Log
+}
+
+func TestFrames(t *testing.T) {
+
+	log := recordMatch(t)
+
+	frames := Frames(log)
+
+	turns := 0
+	for _, round := range log.Result.Rounds {
+		turns += round.Turns
+	}
+
+	require.Len(t, frames, turns)
+
+	first := frames[0]
+	assert.Equal(t, engine.FirstRound, first.Round)
+	assert.Equal(t, 1, first.Turn)
+	assert.Equal(t, first.Discarded, first.Discard[0])
+
+	// after their turn, a player holds as many cards as the round number
+	assert.Len(t, first.Players[first.Seat].Hand, engine.FirstRound)
+
+	// the other player has not had a turn yet, so they are shown their dealt hand
+	assert.Len(t, first.Players[1-first.Seat].Hand, engine.FirstRound)
+	assert.Nil(t, first.Players[1-first.Seat].Sequences)
+}
+
+func TestStore(t *testing.T) {
+
+	store := NewStore(t.TempDir())
+
+	id, err := store.Save(recordMatch(t))
+	require.NoError(t, err)
+
+	summaries, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, id, summaries[0].ID)
+	assert.Equal(t, []string{"grugbot", "bigbrainbot"}, summaries[0].Players)
+
+	log, err := store.Load(id)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), log.Seed)
+
+	_, err = store.Load("../" + id)
+	assert.ErrorIs(t, err, ErrInvalidID)
+}
+
+func TestStoreSameSeed(t *testing.T) {
+
+	store := NewStore(t.TempDir())
+	match := recordMatch(t)
+
+	// the same seed saved many times over, mostly within the same millisecond
+	ids := make([]string, 0)
+	for range 10 {
+		id, err := store.Save(match)
+		require.NoError(t, err)
+		assert.NotContains(t, ids, id)
+
+		ids = append(ids, id)
+	}
+
+	summaries, err := store.List()
+	require.NoError(t, err)
+	assert.Len(t, summaries, len(ids))
+}
+
+func TestStoreSkipsCorruptFiles(t *testing.T) {
+
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	id, err := store.Save(recordMatch(t))
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "corrupt.json"), []byte("{not json"), 0o644))
+
+	summaries, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, id, summaries[0].ID)
+}
@@ -0,0 +1,132 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/timtatt/fivecrowns/game/engine"
+)
+
+// Store keeps recorded match logs as json files in a directory
+
+type Store struct {
+	dir string
+}
+
+type Summary struct {
+	ID      string   `json:"id"`
+	Seed    uint64   `json:"seed"`
+	Players []string `json:"players"`
+	Totals  []int    `json:"totals"`
+	Error   string   `json:"error,omitempty"`
+}
+
+var ErrInvalidID = errors.New("invalid match id")
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// saves the log and returns its id
+func (s *Store) Save(log *engine.MatchLog) (string, error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return "", fmt.Errorf("unable to create match directory: %w", err)
+	}
+
+	prefix := fmt.Sprintf("%s-%d", time.Now().UTC().Format("20060102-150405.000"), log.Seed)
+	id := prefix
+
+	// matches with the same seed can finish in the same millisecond, so never overwrite an existing file
+	f, err := os.OpenFile(s.path(id), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+
+	for n := 2; errors.Is(err, fs.ErrExist); n++ {
+		id = fmt.Sprintf("%s-%d", prefix, n)
+		f, err = os.OpenFile(s.path(id), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("unable to create match file: %w", err)
+	}
+
+	defer f.Close()
+
+	if err := engine.WriteLog(f, log); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (s *Store) Load(id string) (*engine.MatchLog, error) {
+	// the id comes from a url, so make sure it cannot escape the directory
+	if id == "" || filepath.Base(id) != id || strings.HasPrefix(id, ".") {
+		return nil, ErrInvalidID
+	}
+
+	f, err := os.Open(s.path(id))
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to open match %s: %w", id, err)
+	}
+
+	defer f.Close()
+
+	return engine.ReadLog(f)
+}
+
+// lists every match, newest first
+func (s *Store) List() ([]Summary, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to list matches: %w", err)
+	}
+
+	slices.Sort(files)
+	slices.Reverse(files)
+
+	summaries := make([]Summary, 0, len(files))
+
+	for _, file := range files {
+		id := strings.TrimSuffix(filepath.Base(file), ".json")
+
+		log, err := s.Load(id)
+
+		// one unreadable file should not hide every other match
+		if err != nil {
+			slog.Warn("skipping unreadable match", "id", id, "err", err)
+			continue
+		}
+
+		summaries = append(summaries, Summarise(id, log))
+	}
+
+	return summaries, nil
+}
+
+func Summarise(id string, log *engine.MatchLog) Summary {
+	summary := Summary{
+		ID:      id,
+		Seed:    log.Seed,
+		Players: log.Players,
+		Error:   log.Error,
+	}
+
+	if log.Result != nil {
+		summary.Totals = log.Result.Totals
+	}
+
+	return summary
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/timtatt/fivecrowns/batch"
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/httpbot"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/engine"
+	"github.com/timtatt/fivecrowns/ratings"
+	"github.com/timtatt/fivecrowns/tournament"
+)
+
+// plays a table of bots over a range of seeds and prints how each one scored
+func simulate(args []string) error {
+
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	botNames := flags.String("bots", "grugbot,bigbrainbot", "specify the comma separated bots at the table. built in bots by name, remote http bots by url")
+	games := flags.Int("games", 1000, "specify the number of games to play")
+	seed := flags.Uint64("seed", 1, "specify the seed of the first game")
+	concurrency := flags.Int("concurrency", 0, "specify the number of games to play at the same time. defaults to the number of CPUs")
+	rulesName := flags.String("rules", game.HouseRules.Name, "specify the rules the games are played with: house, official")
+	ratingsPath := flags.String("ratings", "", "specify the file where bot ratings are kept e.g. ratings.json. ratings are only updated when set")
+	asJSON := flags.Bool("json", false, "print the report as json")
+	flags.Parse(args)
+
+	table, err := entrants(strings.Split(*botNames, ","))
+
+	if err != nil {
+		return err
+	}
+
+	rules, err := game.LookupRules(*rulesName)
+
+	if err != nil {
+		return err
+	}
+
+	config := batch.Config{
+		Bots:        table,
+		Games:       *games,
+		Seed:        *seed,
+		Concurrency: *concurrency,
+		Rules:       &rules,
+	}
+
+	played, err := batch.Play(config)
+
+	if played == nil {
+		return fmt.Errorf("unable to simulate games: %w", err)
+	}
+
+	report := batch.Summarise(config, played)
+
+	if err != nil {
+		slog.Warn("some games failed", "failed", report.Failed, "err", err)
+	}
+
+	if *ratingsPath != "" {
+		results := make([]engine.MatchResult, 0, len(played))
+		for _, g := range played {
+			if g.Err == nil {
+				results = append(results, g.Result)
+			}
+		}
+
+		if err := ratings.Record(*ratingsPath, results...); err != nil {
+			return err
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(report)
+	}
+
+	return batch.WriteReport(os.Stdout, report)
+}
+
+// finds the built in bot for each name, or plays the bot at the url over http
+func entrants(names []string) ([]tournament.Entrant, error) {
+	out := make([]tournament.Entrant, 0, len(names))
+
+	for _, name := range names {
+		if strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://") {
+			out = append(out, tournament.Entrant{
+				Name:   name,
+				NewBot: func() bots.Bot { return httpbot.NewHTTPBot(name, httpbot.DefaultConfig) },
+			})
+
+			continue
+		}
+
+		newBot, ok := botFactories[name]
+
+		if !ok {
+			return nil, fmt.Errorf("unknown bot: %s", name)
+		}
+
+		out = append(out, tournament.Entrant{Name: name, NewBot: newBot})
+	}
+
+	return out, nil
+}
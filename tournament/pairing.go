@@ -0,0 +1,135 @@
+package tournament
+
+import (
+	"math/rand/v2"
+
+	"github.com/timtatt/fivecrowns/game/engine"
+)
+
+// every combination of TableSize entrants, repeated for each round
+// the seats are rotated each repetition so nobody always sits in the same position
+func roundRobin(n int, config Config) []Match {
+	combinations := combine(n, config.TableSize)
+	matches := make([]Match, 0, len(combinations)*config.Rounds)
+
+	for round := range config.Rounds {
+		for _, seats := range combinations {
+			rotated := make([]int, len(seats))
+			for i := range seats {
+				rotated[i] = seats[(i+round)%len(seats)]
+			}
+
+			matches = append(matches, Match{
+				Round: round,
+				Seed:  matchSeed(config, len(matches)),
+				Seats: rotated,
+			})
+		}
+	}
+
+	return matches
+}
+
+// returns every combination of k indexes out of n
+func combine(n, k int) [][]int {
+	out := make([][]int, 0)
+
+	var walk func(start int, cur []int)
+	walk = func(start int, cur []int) {
+		if len(cur) == k {
+			out = append(out, append([]int{}, cur...))
+			return
+		}
+
+		for i := start; i < n; i++ {
+			walk(i+1, append(cur, i))
+		}
+	}
+
+	walk(0, make([]int, 0, k))
+
+	return out
+}
+
+// splits n entrants into as many tables of at most size as possible
+// tables are kept the same size within one player. if the entrants do not divide evenly, some tables get an extra player
+func tableSizes(n, size int) []int {
+	tables := (n + size - 1) / size
+
+	for tables > 1 && n/tables < engine.MinPlayers {
+		tables -= 1
+	}
+
+	sizes := make([]int, tables)
+	for i := range sizes {
+		sizes[i] = n / tables
+		if i < n%tables {
+			sizes[i] += 1
+		}
+	}
+
+	return sizes
+}
+
+// seats the entrants in order into tables
+func seat(order []int, size int) [][]int {
+	tables := make([][]int, 0)
+
+	for _, tableSize := range tableSizes(len(order), size) {
+		tables = append(tables, order[:tableSize])
+		order = order[tableSize:]
+	}
+
+	return tables
+}
+
+func randomTables(n, size int, r *rand.Rand) [][]int {
+	return seat(r.Perm(n), size)
+}
+
+// seats entrants next to others with similar standings
+// for heads up tables, entrants are kept from playing the same opponent twice where possible
+func swissTables(standings []Standing, n, size int, previous []Match) [][]int {
+	order := make([]int, len(standings))
+	for i, standing := range standings {
+		order[i] = standing.entrant
+	}
+
+	if size != 2 {
+		return seat(order, size)
+	}
+
+	played := make(map[[2]int]bool)
+	for _, match := range previous {
+		for _, a := range match.Seats {
+			for _, b := range match.Seats {
+				played[[2]int{a, b}] = true
+			}
+		}
+	}
+
+	paired := make([]int, 0, n)
+	remaining := order
+
+	for len(remaining) >= 2 {
+		opponent := 1
+		for i := 1; i < len(remaining); i++ {
+			if !played[[2]int{remaining[0], remaining[i]}] {
+				opponent = i
+				break
+			}
+		}
+
+		paired = append(paired, remaining[0], remaining[opponent])
+
+		next := make([]int, 0, len(remaining)-2)
+		for i, entrant := range remaining {
+			if i != 0 && i != opponent {
+				next = append(next, entrant)
+			}
+		}
+		remaining = next
+	}
+
+	return seat(append(paired, remaining...), size)
+}
@@ -0,0 +1,80 @@
+package tournament
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+)
+
+type Standing struct {
+	Name    string
+	Matches int
+	Wins    int
+	// number of rounds (3-13) played across every match
+	Rounds     int
+	TotalScore int
+	// average penalty per round
+	AverageScore float64
+
+	entrant int
+}
+
+// ranks the entrants by wins, then by lowest average score per round
+// failed matches are not counted
+func Standings(entrants []Entrant, matches []Match) []Standing {
+	standings := make([]Standing, len(entrants))
+
+	for i, entrant := range entrants {
+		standings[i] = Standing{
+			Name:    entrant.Name,
+			entrant: i,
+		}
+	}
+
+	for _, match := range matches {
+		if match.Err != nil {
+			continue
+		}
+
+		for _, winner := range match.Result.Winners() {
+			standings[match.Seats[winner]].Wins += 1
+		}
+
+		for i, seat := range match.Seats {
+			standings[seat].Matches += 1
+			standings[seat].Rounds += len(match.Result.Rounds)
+			standings[seat].TotalScore += match.Result.Totals[i]
+		}
+	}
+
+	for i := range standings {
+		if standings[i].Rounds > 0 {
+			standings[i].AverageScore = float64(standings[i].TotalScore) / float64(standings[i].Rounds)
+		}
+	}
+
+	slices.SortStableFunc(standings, func(a, b Standing) int {
+		if a.Wins != b.Wins {
+			return b.Wins - a.Wins
+		}
+
+		return cmp.Compare(a.AverageScore, b.AverageScore)
+	})
+
+	return standings
+}
+
+// writes the standings as a table
+func WriteStandings(w io.Writer, standings []Standing) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "#\tbot\tmatches\twins\tavg score/round\ttotal score")
+
+	for i, s := range standings {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%.2f\t%d\n", i+1, s.Name, s.Matches, s.Wins, s.AverageScore, s.TotalScore)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,167 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/engine"
+)
+
+// Tournament schedules many matches between a set of bots and ranks them
+// every match gets its own seed derived from the tournament seed, so a tournament can be rerun exactly
+
+type Format string
+
+const (
+	// every combination of TableSize entrants plays Rounds matches together
+	FormatRoundRobin Format = "round-robin"
+	// each round, entrants with similar standings are seated together
+	FormatSwiss Format = "swiss"
+	// each round, entrants are seated at random tables of TableSize
+	FormatTables Format = "tables"
+)
+
+// Entrant creates a fresh bot for every match so bots which keep state between turns can play concurrently
+type Entrant struct {
+	Name   string
+	NewBot func() bots.Bot
+}
+
+type Config struct {
+	Format Format
+	// number of players at each table
+	TableSize int
+	// number of tournament rounds. for round robin, the number of matches played by each combination
+	Rounds int
+	Seed   uint64
+	// number of matches to play at the same time. defaults to the number of CPUs
+	Concurrency int
+	// the rules every match is played with, nil for the house rules
+	Rules *game.RuleSet
+}
+
+type Match struct {
+	// tournament round the match was played in
+	Round int
+	Seed  uint64
+	// index of each entrant seated at the table
+	Seats  []int
+	Result engine.MatchResult
+	Err    error
+}
+
+type Result struct {
+	Matches   []Match
+	Standings []Standing
+}
+
+// plays every match in the tournament
+// matches which fail are kept with their error and left out of the standings
+func Run(entrants []Entrant, config Config) (Result, error) {
+
+	if config.TableSize < engine.MinPlayers || config.TableSize > engine.MaxPlayers {
+		return Result{}, fmt.Errorf("invalid table size: %d", config.TableSize)
+	} else if len(entrants) < config.TableSize {
+		return Result{}, fmt.Errorf("%d entrants cannot fill a table of %d", len(entrants), config.TableSize)
+	} else if config.Rounds < 1 {
+		return Result{}, fmt.Errorf("invalid number of rounds: %d", config.Rounds)
+	}
+
+	if config.Concurrency < 1 {
+		config.Concurrency = runtime.NumCPU()
+	}
+
+	r := rand.New(rand.NewPCG(config.Seed, 0))
+	matches := make([]Match, 0)
+
+	switch config.Format {
+	case FormatRoundRobin:
+		matches = roundRobin(len(entrants), config)
+		play(entrants, matches, config)
+	case FormatTables:
+		for round := range config.Rounds {
+			tables := randomTables(len(entrants), config.TableSize, r)
+			matches = append(matches, play(entrants, newMatches(round, len(matches), tables, config), config)...)
+		}
+	case FormatSwiss:
+		for round := range config.Rounds {
+			tables := swissTables(Standings(entrants, matches), len(entrants), config.TableSize, matches)
+			matches = append(matches, play(entrants, newMatches(round, len(matches), tables, config), config)...)
+		}
+	default:
+		return Result{}, fmt.Errorf("unknown format: %s", config.Format)
+	}
+
+	var errs error
+	for _, match := range matches {
+		errs = errors.Join(errs, match.Err)
+	}
+
+	return Result{
+		Matches:   matches,
+		Standings: Standings(entrants, matches),
+	}, errs
+}
+
+// the seed of each match only depends on the tournament seed and the match number
+func matchSeed(config Config, number int) uint64 {
+	return config.Seed*1_000_003 + uint64(number)
+}
+
+func newMatches(round int, offset int, tables [][]int, config Config) []Match {
+	matches := make([]Match, len(tables))
+
+	for i, seats := range tables {
+		matches[i] = Match{
+			Round: round,
+			Seed:  matchSeed(config, offset+i),
+			Seats: seats,
+		}
+	}
+
+	return matches
+}
+
+// plays the matches concurrently, filling in their results
+func play(entrants []Entrant, matches []Match, config Config) []Match {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, config.Concurrency)
+
+	for i := range matches {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(match *Match) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			players := make([]engine.Player, len(match.Seats))
+			for j, seat := range match.Seats {
+				players[j] = engine.Player{
+					Name: entrants[seat].Name,
+					Bot:  entrants[seat].NewBot(),
+				}
+			}
+
+			e := engine.NewEngine(players, match.Seed)
+			if config.Rules != nil {
+				e.Rules = *config.Rules
+			}
+
+			match.Result, match.Err = e.Play()
+
+			if match.Err != nil {
+				match.Err = fmt.Errorf("match with seed %d failed: %w", match.Seed, match.Err)
+			}
+		}(&matches[i])
+	}
+
+	wg.Wait()
+
+	return matches
+}
@@ -0,0 +1,105 @@
+package tournament
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots/bigbrainbot"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/bots/smoothbrainbot"
+)
+
+var entrants = []Entrant{
+	{Name: "smoothbrainbot", NewBot: smoothbrainbot.NewSmoothBrainBot},
+	{Name: "grugbot", NewBot: grugbot.NewGrugBot},
+	{Name: "bigbrainbot", NewBot: bigbrainbot.NewBigBrainBot},
+	{Name: "grugbot2", NewBot: grugbot.NewGrugBot},
+}
+
+func TestRoundRobin(t *testing.T) {
+
+	config := Config{
+		Format:    FormatRoundRobin,
+		TableSize: 2,
+		Rounds:    1,
+		Seed:      42,
+	}
+
+	res, err := Run(entrants[1:], config)
+
+	require.NoError(t, err)
+	assert.Len(t, res.Matches, 3)
+	require.Len(t, res.Standings, 3)
+
+	for _, standing := range res.Standings {
+		assert.Equal(t, 2, standing.Matches)
+		assert.Equal(t, 22, standing.Rounds)
+	}
+
+	// the same seed should give the same tournament
+	again, err := Run(entrants[1:], config)
+
+	require.NoError(t, err)
+	assert.Equal(t, res.Standings, again.Standings)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteStandings(&out, res.Standings))
+	assert.Contains(t, out.String(), "bigbrainbot")
+}
+
+func TestSwiss(t *testing.T) {
+
+	res, err := Run(entrants, Config{
+		Format:    FormatSwiss,
+		TableSize: 2,
+		Rounds:    3,
+		Seed:      7,
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, res.Matches, 6)
+
+	// with 4 entrants and 3 rounds, nobody should play the same opponent twice
+	opponents := make(map[[2]int]int)
+	for _, match := range res.Matches {
+		opponents[[2]int{min(match.Seats[0], match.Seats[1]), max(match.Seats[0], match.Seats[1])}] += 1
+	}
+
+	assert.Len(t, opponents, 6)
+}
+
+func TestTableSizes(t *testing.T) {
+
+	cases := []struct {
+		Entrants  int
+		TableSize int
+		Expected  []int
+	}{
+		{Entrants: 8, TableSize: 4, Expected: []int{4, 4}},
+		{Entrants: 9, TableSize: 4, Expected: []int{3, 3, 3}},
+		{Entrants: 3, TableSize: 2, Expected: []int{3}},
+		{Entrants: 5, TableSize: 2, Expected: []int{3, 2}},
+	}
+
+	for _, tc := range cases {
+		t.Run("should split entrants into tables", func(t *testing.T) {
+			assert.Equal(t, tc.Expected, tableSizes(tc.Entrants, tc.TableSize))
+		})
+	}
+}
+
+func TestRandomTables(t *testing.T) {
+
+	tables := randomTables(7, 3, rand.New(rand.NewPCG(1, 1)))
+
+	seated := 0
+	for _, table := range tables {
+		seated += len(table)
+	}
+
+	assert.Len(t, tables, 3)
+	assert.Equal(t, 7, seated)
+}
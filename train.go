@@ -0,0 +1,131 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/training"
+)
+
+// plays a bot against itself and writes a sample from every discard to a dataset
+func selfPlay(args []string) error {
+
+	flags := flag.NewFlagSet("selfplay", flag.ExitOnError)
+	botName := flags.String("bot", "grugbot", "specify the bot which plays every seat")
+	games := flags.Int("games", 100, "specify the number of games to play")
+	players := flags.Int("players", 3, "specify the number of players at each table")
+	seed := flags.Uint64("seed", 1, "specify the seed of the first game")
+	out := flags.String("out", "dataset.jsonl", "specify the file the dataset is written to")
+	rulesName := flags.String("rules", game.HouseRules.Name, "specify the rules the games are played with: house, official")
+	flags.Parse(args)
+
+	newBot, ok := botFactories[*botName]
+
+	if !ok {
+		return fmt.Errorf("unknown bot: %s", *botName)
+	}
+
+	rules, err := game.LookupRules(*rulesName)
+
+	if err != nil {
+		return err
+	}
+
+	samples, err := training.SelfPlay(training.SelfPlayConfig{
+		Games:   *games,
+		Players: *players,
+		Seed:    *seed,
+		NewBot:  newBot,
+		Rules:   &rules,
+	})
+
+	if err != nil {
+		return fmt.Errorf("unable to play games: %w", err)
+	}
+
+	f, err := os.Create(*out)
+
+	if err != nil {
+		return fmt.Errorf("unable to create dataset: %w", err)
+	}
+
+	defer f.Close()
+
+	if err := training.WriteDataset(f, samples); err != nil {
+		return err
+	}
+
+	slog.Info("wrote dataset", "samples", len(samples), "path", *out)
+
+	return nil
+}
+
+// fits a model to a dataset, holding back every fifth sample to check it against
+func train(args []string) error {
+
+	flags := flag.NewFlagSet("train", flag.ExitOnError)
+	data := flags.String("data", "dataset.jsonl", "specify the dataset to train on")
+	kind := flags.String("model", training.KindLinear, "specify the kind of model: linear, mlp")
+	out := flags.String("out", "model.json", "specify the file the model is written to")
+	l2 := flags.Float64("l2", training.DefaultFitConfig.L2, "specify the penalty on large weights")
+	hidden := flags.Int("hidden", training.DefaultFitConfig.Hidden, "specify the number of hidden units in an mlp")
+	epochs := flags.Int("epochs", training.DefaultFitConfig.Epochs, "specify the number of passes over the dataset for an mlp")
+	rate := flags.Float64("rate", training.DefaultFitConfig.LearningRate, "specify the learning rate for an mlp")
+	flags.Parse(args)
+
+	f, err := os.Open(*data)
+
+	if err != nil {
+		return fmt.Errorf("unable to open dataset: %w", err)
+	}
+
+	samples, err := training.ReadDataset(f)
+	f.Close()
+
+	if err != nil {
+		return err
+	}
+
+	fit := make([]training.Sample, 0, len(samples))
+	validation := make([]training.Sample, 0, len(samples)/5)
+
+	for i, sample := range samples {
+		if i%5 == 4 {
+			validation = append(validation, sample)
+		} else {
+			fit = append(fit, sample)
+		}
+	}
+
+	m, err := training.Fit(*kind, fit, training.FitConfig{
+		L2:           *l2,
+		Hidden:       *hidden,
+		Epochs:       *epochs,
+		LearningRate: *rate,
+		Seed:         training.DefaultFitConfig.Seed,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	slog.Info("trained model",
+		"kind", *kind,
+		"samples", len(fit),
+		"trainError", training.MeanSquaredError(m, fit),
+		"validationError", training.MeanSquaredError(m, validation),
+	)
+
+	f, err = os.Create(*out)
+
+	if err != nil {
+		return fmt.Errorf("unable to create model: %w", err)
+	}
+
+	defer f.Close()
+
+	return training.SaveModel(f, m)
+}
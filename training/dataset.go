@@ -0,0 +1,108 @@
+package training
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/engine"
+)
+
+// a Sample pairs the features of a hand with the score it ended the round with
+// datasets are written as one json sample per line so they can be appended to and streamed
+
+type Sample struct {
+	Features []float64 `json:"features"`
+	Score    float64   `json:"score"`
+}
+
+// extracts a sample from every discard in a finished match
+// rounds which were abandoned because nobody went out are skipped, as they say nothing about how hands finish
+func Samples(log *engine.MatchLog) ([]Sample, error) {
+	if log.Result == nil {
+		return nil, errors.New("unable to label an unfinished match")
+	}
+
+	samples := make([]Sample, 0)
+
+	first := engine.FirstRound
+	if log.Rules != nil {
+		first = log.Rules.FirstRound
+	}
+
+	for _, event := range log.Events {
+		round := log.Result.Rounds[event.Round-first]
+
+		if event.Discard == nil || round.WentOut == -1 {
+			continue
+		}
+
+		hand := slices.Clone(event.Request.Hand)
+		card, err := game.DecodeCard(event.Discard.Card)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode discard: %w", err)
+		}
+
+		if idx := slices.Index(hand, card); idx != -1 {
+			hand = slices.Delete(hand, idx, idx+1)
+		}
+
+		samples = append(samples, Sample{
+			Features: Features(State{
+				Round:   event.Round,
+				Players: len(log.Players),
+				Turn:    event.Turn,
+				Hand:    hand,
+				Rules:   log.Rules,
+			}),
+			Score: float64(round.Scores[event.Seat]),
+		})
+	}
+
+	return samples, nil
+}
+
+func WriteDataset(w io.Writer, samples []Sample) error {
+	enc := json.NewEncoder(w)
+
+	for _, sample := range samples {
+		if err := enc.Encode(sample); err != nil {
+			return fmt.Errorf("unable to encode sample: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func ReadDataset(r io.Reader) ([]Sample, error) {
+	samples := make([]Sample, 0)
+	scanner := bufio.NewScanner(r)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var sample Sample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			return nil, fmt.Errorf("unable to decode sample on line %d: %w", line, err)
+		}
+
+		if len(sample.Features) != len(FeatureNames) {
+			return nil, fmt.Errorf("sample on line %d has %d features, expected %d", line, len(sample.Features), len(FeatureNames))
+		}
+
+		samples = append(samples, sample)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read dataset: %w", err)
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,85 @@
+package training
+
+import (
+	"github.com/timtatt/fivecrowns/game"
+)
+
+// Features describe a hand at the end of a turn, after the discard
+// they are what a model sees when it predicts the score the hand will end the round with
+
+var FeatureNames = []string{
+	"round",
+	"players",
+	"turn",
+	"penalty",
+	"leftover",
+	"wilds",
+	"used",
+	"pairs",
+	"gaps",
+	"highest",
+}
+
+type State struct {
+	Round   int
+	Players int
+	// turn number within the round, starting at 1
+	Turn int
+	Hand []game.Card
+	// the rules the round is played with, nil for the house rules
+	Rules *game.RuleSet
+}
+
+func Features(s State) []float64 {
+	rules := game.HouseRules
+	if s.Rules != nil {
+		rules = *s.Rules
+	}
+
+	arrangement := rules.OptimalArrangement(s.Hand, s.Round)
+
+	wilds := 0
+	for _, card := range s.Hand {
+		if card.IsWild(s.Round) {
+			wilds += 1
+		}
+	}
+
+	// leftover cards which are one card away from a set or a run
+	pairs := 0
+	gaps := 0
+	highest := 0
+
+	for i, card := range arrangement.Leftover {
+		highest = max(highest, rules.ScoreCard(card, s.Round))
+
+		for _, other := range arrangement.Leftover[i+1:] {
+			if card.Number == other.Number {
+				pairs += 1
+			} else if card.Suite == other.Suite && abs(card.Number-other.Number) <= 2 {
+				gaps += 1
+			}
+		}
+	}
+
+	return []float64{
+		float64(s.Round),
+		float64(s.Players),
+		float64(s.Turn),
+		float64(arrangement.Penalty),
+		float64(len(arrangement.Leftover)),
+		float64(wilds),
+		float64(len(s.Hand) - len(arrangement.Leftover)),
+		float64(pairs),
+		float64(gaps),
+		float64(highest),
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,414 @@
+package training
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+)
+
+// a Model predicts the score a hand will end the round with from its features
+// features are standardised with the mean and spread of the training set before they reach the model
+
+type Model interface {
+	Predict(features []float64) float64
+}
+
+const (
+	KindLinear = "linear"
+	KindMLP    = "mlp"
+)
+
+type Scaler struct {
+	Mean  []float64 `json:"mean"`
+	Scale []float64 `json:"scale"`
+}
+
+func NewScaler(samples []Sample) Scaler {
+	n := len(FeatureNames)
+	s := Scaler{
+		Mean:  make([]float64, n),
+		Scale: make([]float64, n),
+	}
+
+	for _, sample := range samples {
+		for i, x := range sample.Features {
+			s.Mean[i] += x
+		}
+	}
+
+	for i := range s.Mean {
+		s.Mean[i] /= float64(len(samples))
+	}
+
+	for _, sample := range samples {
+		for i, x := range sample.Features {
+			s.Scale[i] += (x - s.Mean[i]) * (x - s.Mean[i])
+		}
+	}
+
+	// features which never change are left unscaled, allowing for rounding in the mean
+	for i, total := range s.Scale {
+		s.Scale[i] = math.Sqrt(total / float64(len(samples)))
+
+		if s.Scale[i] < 1e-9 {
+			s.Scale[i] = 1
+		}
+	}
+
+	return s
+}
+
+func (s Scaler) Transform(features []float64) []float64 {
+	out := make([]float64, len(features))
+
+	for i, x := range features {
+		out[i] = (x - s.Mean[i]) / s.Scale[i]
+	}
+
+	return out
+}
+
+type Linear struct {
+	Scaler  Scaler    `json:"scaler"`
+	Weights []float64 `json:"weights"`
+	Bias    float64   `json:"bias"`
+}
+
+func (m *Linear) Predict(features []float64) float64 {
+	return m.Bias + dot(m.Weights, m.Scaler.Transform(features))
+}
+
+// MLP has a single hidden layer of tanh units
+type MLP struct {
+	Scaler Scaler `json:"scaler"`
+	// weights of each hidden unit
+	Hidden     [][]float64 `json:"hidden"`
+	HiddenBias []float64   `json:"hiddenBias"`
+	Output     []float64   `json:"output"`
+	OutputBias float64     `json:"outputBias"`
+}
+
+func (m *MLP) Predict(features []float64) float64 {
+	_, out := m.forward(m.Scaler.Transform(features))
+
+	return out
+}
+
+// returns the activation of each hidden unit and the prediction
+func (m *MLP) forward(x []float64) ([]float64, float64) {
+	hidden := make([]float64, len(m.Hidden))
+
+	for j, weights := range m.Hidden {
+		hidden[j] = math.Tanh(m.HiddenBias[j] + dot(weights, x))
+	}
+
+	return hidden, m.OutputBias + dot(m.Output, hidden)
+}
+
+type FitConfig struct {
+	// strength of the penalty on large weights
+	L2 float64
+	// number of hidden units in an mlp
+	Hidden       int
+	Epochs       int
+	LearningRate float64
+	Seed         uint64
+}
+
+var DefaultFitConfig = FitConfig{
+	L2:           1e-3,
+	Hidden:       8,
+	Epochs:       60,
+	LearningRate: 0.0005,
+	Seed:         1,
+}
+
+func Fit(kind string, samples []Sample, config FitConfig) (Model, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("unable to fit a model without samples")
+	}
+
+	switch kind {
+	case KindLinear:
+		return FitLinear(samples, config)
+	case KindMLP:
+		return FitMLP(samples, config), nil
+	default:
+		return nil, fmt.Errorf("unknown model: %s", kind)
+	}
+}
+
+// fits a linear model with ridge regression, solving the normal equations exactly
+func FitLinear(samples []Sample, config FitConfig) (*Linear, error) {
+	scaler := NewScaler(samples)
+
+	// the last column is the bias, which is not penalised
+	n := len(FeatureNames) + 1
+	a := make([][]float64, n)
+	b := make([]float64, n)
+
+	for i := range a {
+		a[i] = make([]float64, n)
+	}
+
+	for _, sample := range samples {
+		x := append(scaler.Transform(sample.Features), 1)
+
+		for i := range n {
+			b[i] += x[i] * sample.Score
+
+			for j := range n {
+				a[i][j] += x[i] * x[j]
+			}
+		}
+	}
+
+	for i := range n - 1 {
+		a[i][i] += config.L2 * float64(len(samples))
+	}
+
+	w, err := solve(a, b)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to fit linear model: %w", err)
+	}
+
+	return &Linear{
+		Scaler:  scaler,
+		Weights: w[:n-1],
+		Bias:    w[n-1],
+	}, nil
+}
+
+// fits an mlp by stochastic gradient descent on the squared error
+func FitMLP(samples []Sample, config FitConfig) *MLP {
+	r := rand.New(rand.NewPCG(config.Seed, 0))
+	scaler := NewScaler(samples)
+	inputs := len(FeatureNames)
+	hidden := max(config.Hidden, 1)
+
+	mean := 0.0
+	for _, sample := range samples {
+		mean += sample.Score / float64(len(samples))
+	}
+
+	m := &MLP{
+		Scaler:     scaler,
+		Hidden:     make([][]float64, hidden),
+		HiddenBias: make([]float64, hidden),
+		Output:     make([]float64, hidden),
+		OutputBias: mean,
+	}
+
+	for j := range m.Hidden {
+		m.Hidden[j] = make([]float64, inputs)
+
+		for i := range m.Hidden[j] {
+			m.Hidden[j][i] = r.NormFloat64() / math.Sqrt(float64(inputs))
+		}
+
+		m.Output[j] = r.NormFloat64() / math.Sqrt(float64(hidden))
+	}
+
+	xs := make([][]float64, len(samples))
+	for i, sample := range samples {
+		xs[i] = scaler.Transform(sample.Features)
+	}
+
+	order := r.Perm(len(samples))
+
+	for range config.Epochs {
+		r.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+
+		for _, idx := range order {
+			x := xs[idx]
+			h, out := m.forward(x)
+			grad := out - samples[idx].Score
+
+			for j := range m.Hidden {
+				// the gradient through the tanh unit, taken before the output weight is updated
+				back := grad * m.Output[j] * (1 - h[j]*h[j])
+
+				m.Output[j] -= config.LearningRate * (grad*h[j] + config.L2*m.Output[j])
+
+				for i := range x {
+					m.Hidden[j][i] -= config.LearningRate * (back*x[i] + config.L2*m.Hidden[j][i])
+				}
+
+				m.HiddenBias[j] -= config.LearningRate * back
+			}
+
+			m.OutputBias -= config.LearningRate * grad
+		}
+	}
+
+	return m
+}
+
+// the mean squared error of the model's predictions
+func MeanSquaredError(m Model, samples []Sample) float64 {
+	total := 0.0
+
+	for _, sample := range samples {
+		diff := m.Predict(sample.Features) - sample.Score
+		total += diff * diff
+	}
+
+	return total / float64(len(samples))
+}
+
+// the kind of model is saved alongside it so it can be loaded without knowing it up front
+type savedModel struct {
+	Kind   string  `json:"kind"`
+	Linear *Linear `json:"linear,omitempty"`
+	MLP    *MLP    `json:"mlp,omitempty"`
+}
+
+func SaveModel(w io.Writer, m Model) error {
+	var saved savedModel
+
+	switch m := m.(type) {
+	case *Linear:
+		saved = savedModel{Kind: KindLinear, Linear: m}
+	case *MLP:
+		saved = savedModel{Kind: KindMLP, MLP: m}
+	default:
+		return fmt.Errorf("unable to save model of type %T", m)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(saved); err != nil {
+		return fmt.Errorf("unable to encode model: %w", err)
+	}
+
+	return nil
+}
+
+func LoadModel(r io.Reader) (Model, error) {
+	var saved savedModel
+
+	if err := json.NewDecoder(r).Decode(&saved); err != nil {
+		return nil, fmt.Errorf("unable to decode model: %w", err)
+	}
+
+	var m interface {
+		Model
+		validate() error
+	}
+
+	switch {
+	case saved.Kind == KindLinear && saved.Linear != nil:
+		m = saved.Linear
+	case saved.Kind == KindMLP && saved.MLP != nil:
+		m = saved.MLP
+	default:
+		return nil, fmt.Errorf("unknown model: %s", saved.Kind)
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s model: %w", saved.Kind, err)
+	}
+
+	return m, nil
+}
+
+// models trained on a different set of features would panic or predict nonsense
+func (s Scaler) validate() error {
+	if len(s.Mean) != len(FeatureNames) || len(s.Scale) != len(FeatureNames) {
+		return fmt.Errorf("scaler has %d means and %d scales, expected %d", len(s.Mean), len(s.Scale), len(FeatureNames))
+	}
+
+	return nil
+}
+
+func (m *Linear) validate() error {
+	if err := m.Scaler.validate(); err != nil {
+		return err
+	}
+
+	if len(m.Weights) != len(FeatureNames) {
+		return fmt.Errorf("%d weights, expected %d", len(m.Weights), len(FeatureNames))
+	}
+
+	return nil
+}
+
+func (m *MLP) validate() error {
+	if err := m.Scaler.validate(); err != nil {
+		return err
+	}
+
+	if len(m.HiddenBias) != len(m.Hidden) || len(m.Output) != len(m.Hidden) {
+		return fmt.Errorf("%d hidden units with %d biases and %d outputs", len(m.Hidden), len(m.HiddenBias), len(m.Output))
+	}
+
+	for j, weights := range m.Hidden {
+		if len(weights) != len(FeatureNames) {
+			return fmt.Errorf("hidden unit %d has %d weights, expected %d", j, len(weights), len(FeatureNames))
+		}
+	}
+
+	return nil
+}
+
+func dot(a, b []float64) float64 {
+	total := 0.0
+
+	for i := range a {
+		total += a[i] * b[i]
+	}
+
+	return total
+}
+
+// solves a x = b by gaussian elimination with partial pivoting
+func solve(a [][]float64, b []float64) ([]float64, error) {
+	n := len(b)
+
+	for col := range n {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, errors.New("singular matrix")
+		}
+
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+
+			for k := col; k < n; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+
+			b[row] -= factor * b[col]
+		}
+	}
+
+	x := make([]float64, n)
+
+	for row := n - 1; row >= 0; row-- {
+		total := b[row]
+
+		for k := row + 1; k < n; k++ {
+			total -= a[row][k] * x[k]
+		}
+
+		x[row] = total / a[row][row]
+	}
+
+	return x, nil
+}
@@ -0,0 +1,91 @@
+package training
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/game/engine"
+)
+
+// SelfPlay plays a bot against copies of itself and records a sample from every discard
+// game i is seeded with Seed+i, so a dataset can be regenerated exactly
+
+type SelfPlayConfig struct {
+	Games int
+	// number of players at each table
+	Players int
+	Seed    uint64
+	// number of games to play at the same time. defaults to the number of CPUs
+	Concurrency int
+	// creates a fresh bot for every seat so bots which keep state between turns can play concurrently
+	NewBot func() bots.Bot
+	// the rules every game is played with, nil for the house rules
+	Rules *game.RuleSet
+}
+
+func SelfPlay(config SelfPlayConfig) ([]Sample, error) {
+
+	if config.Players < engine.MinPlayers || config.Players > engine.MaxPlayers {
+		return nil, fmt.Errorf("invalid number of players: %d", config.Players)
+	} else if config.Games < 1 {
+		return nil, fmt.Errorf("invalid number of games: %d", config.Games)
+	}
+
+	if config.Concurrency < 1 {
+		config.Concurrency = runtime.NumCPU()
+	}
+
+	games := make([][]Sample, config.Games)
+	errs := make([]error, config.Games)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, config.Concurrency)
+
+	for i := range config.Games {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			games[i], errs[i] = playGame(config, config.Seed+uint64(i))
+		}()
+	}
+
+	wg.Wait()
+
+	samples := make([]Sample, 0)
+	for _, game := range games {
+		samples = append(samples, game...)
+	}
+
+	return samples, errors.Join(errs...)
+}
+
+func playGame(config SelfPlayConfig, seed uint64) ([]Sample, error) {
+	players := make([]engine.Player, config.Players)
+	for i := range players {
+		players[i] = engine.Player{
+			Name: fmt.Sprintf("seat-%d", i),
+			Bot:  config.NewBot(),
+		}
+	}
+
+	e := engine.NewEngine(players, seed)
+	e.Log = &engine.MatchLog{}
+
+	if config.Rules != nil {
+		e.Rules = *config.Rules
+	}
+
+	if _, err := e.Play(); err != nil {
+		return nil, fmt.Errorf("game with seed %d failed: %w", seed, err)
+	}
+
+	return Samples(e.Log)
+}
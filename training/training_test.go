@@ -0,0 +1,173 @@
+package training
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/game"
+)
+
+func TestFeatures(t *testing.T) {
+	hand, err := game.DecodeCards(strings.Split("9-R:9-B:9-Y:4-X:5-X:13-G:*", ":"))
+	require.NoError(t, err)
+
+	features := Features(State{Round: 7, Players: 3, Turn: 4, Hand: hand})
+
+	require.Len(t, features, len(FeatureNames))
+
+	expected := map[string]float64{
+		"round":   7,
+		"players": 3,
+		"turn":    4,
+		// the joker completes 4-X:5-X, leaving the 13-G
+		"penalty":  13,
+		"leftover": 1,
+		"wilds":    1,
+		"used":     6,
+		"pairs":    0,
+		"gaps":     0,
+		"highest":  13,
+	}
+
+	for i, name := range FeatureNames {
+		assert.Equal(t, expected[name], features[i], name)
+	}
+}
+
+func TestSelfPlay(t *testing.T) {
+	samples, err := SelfPlay(SelfPlayConfig{
+		Games:   2,
+		Players: 2,
+		Seed:    1,
+		NewBot:  grugbot.NewGrugBot,
+	})
+
+	require.NoError(t, err)
+	require.NotEmpty(t, samples)
+
+	for _, sample := range samples {
+		assert.Len(t, sample.Features, len(FeatureNames))
+		assert.GreaterOrEqual(t, sample.Score, 0.0)
+	}
+
+	// the same seeds give the same dataset
+	again, err := SelfPlay(SelfPlayConfig{
+		Games:   2,
+		Players: 2,
+		Seed:    1,
+		NewBot:  grugbot.NewGrugBot,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, samples, again)
+}
+
+func TestDataset(t *testing.T) {
+	samples := []Sample{
+		{Features: make([]float64, len(FeatureNames)), Score: 12},
+		{Features: []float64{3, 2, 1, 5, 1, 0, 2, 0, 0, 5}, Score: 0},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteDataset(&buf, samples))
+
+	read, err := ReadDataset(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, samples, read)
+
+	_, err = ReadDataset(strings.NewReader(`{"features": [1, 2], "score": 3}`))
+	assert.Error(t, err)
+}
+
+// scores made up from a known function of the features, so each model has something to learn
+func syntheticSamples(n int) []Sample {
+	r := rand.New(rand.NewPCG(1, 2))
+	samples := make([]Sample, n)
+
+	for i := range samples {
+		features := make([]float64, len(FeatureNames))
+		for j := range features {
+			features[j] = r.Float64() * 10
+		}
+
+		samples[i] = Sample{
+			Features: features,
+			Score:    2*features[3] - features[5] + 0.5*features[3]*features[9]/10 + 4,
+		}
+	}
+
+	return samples
+}
+
+func TestFit(t *testing.T) {
+	samples := syntheticSamples(500)
+
+	baseline := 0.0
+	mean := 0.0
+	for _, sample := range samples {
+		mean += sample.Score / float64(len(samples))
+	}
+	for _, sample := range samples {
+		baseline += (sample.Score - mean) * (sample.Score - mean) / float64(len(samples))
+	}
+
+	for _, kind := range []string{KindLinear, KindMLP} {
+		t.Run(kind, func(t *testing.T) {
+			m, err := Fit(kind, samples, DefaultFitConfig)
+			require.NoError(t, err)
+
+			// the model explains most of the variance in the scores
+			assert.Less(t, MeanSquaredError(m, samples), baseline/10)
+
+			var buf bytes.Buffer
+			require.NoError(t, SaveModel(&buf, m))
+
+			loaded, err := LoadModel(&buf)
+			require.NoError(t, err)
+			assert.InDelta(t, m.Predict(samples[0].Features), loaded.Predict(samples[0].Features), 1e-9)
+		})
+	}
+
+	_, err := Fit("tree", samples, DefaultFitConfig)
+	assert.Error(t, err)
+}
+
+func TestLoadModelShape(t *testing.T) {
+	n := len(FeatureNames)
+	scaler := Scaler{Mean: make([]float64, n), Scale: make([]float64, n)}
+	short := Scaler{Mean: make([]float64, n-1), Scale: make([]float64, n-1)}
+
+	cases := []struct {
+		Name  string
+		Model Model
+		Valid bool
+	}{
+		{Name: "linear", Model: &Linear{Scaler: scaler, Weights: make([]float64, n)}, Valid: true},
+		{Name: "linear short weights", Model: &Linear{Scaler: scaler, Weights: make([]float64, n-1)}},
+		{Name: "linear short scaler", Model: &Linear{Scaler: short, Weights: make([]float64, n)}},
+		{Name: "mlp", Model: &MLP{Scaler: scaler, Hidden: [][]float64{make([]float64, n)}, HiddenBias: []float64{0}, Output: []float64{0}}, Valid: true},
+		{Name: "mlp short hidden", Model: &MLP{Scaler: scaler, Hidden: [][]float64{make([]float64, n-1)}, HiddenBias: []float64{0}, Output: []float64{0}}},
+		{Name: "mlp missing output", Model: &MLP{Scaler: scaler, Hidden: [][]float64{make([]float64, n)}, HiddenBias: []float64{0}}},
+		{Name: "mlp short scaler", Model: &MLP{Scaler: short, Hidden: [][]float64{make([]float64, n)}, HiddenBias: []float64{0}, Output: []float64{0}}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, SaveModel(&buf, c.Model))
+
+			_, err := LoadModel(&buf)
+
+			if c.Valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
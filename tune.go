@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/timtatt/fivecrowns/bots/heuristicbot"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/tuning"
+)
+
+// searches for heuristicbot weights which score well against a set of opponents
+func tune(args []string) error {
+
+	flags := flag.NewFlagSet("tune", flag.ExitOnError)
+	method := flags.String("method", string(tuning.MethodEvolve), "specify the search method: grid, random, evolve")
+	budget := flags.Int("budget", 50, "specify the number of weights to try")
+	matches := flags.Int("matches", 4, "specify the number of matches played by each candidate")
+	opponentNames := flags.String("opponents", "grugbot,bigbrainbot", "specify the comma separated bots each candidate plays against")
+	seed := flags.Uint64("seed", 1, "specify the seed of the search")
+	start := flags.String("start", "", "specify a weights file for the evolutionary search to start from")
+	out := flags.String("out", "weights.json", "specify the file the best weights are written to")
+	rulesName := flags.String("rules", game.HouseRules.Name, "specify the rules the games are played with: house, official")
+	flags.Parse(args)
+
+	rules, err := game.LookupRules(*rulesName)
+
+	if err != nil {
+		return err
+	}
+
+	opponents, err := entrants(strings.Split(*opponentNames, ","))
+
+	if err != nil {
+		return err
+	}
+
+	config := tuning.Config{
+		Method:    tuning.Method(*method),
+		Budget:    *budget,
+		Opponents: opponents,
+		Matches:   *matches,
+		Seed:      *seed,
+		Rules:     &rules,
+	}
+
+	if *start != "" {
+		w, err := heuristicbot.LoadWeights(*start)
+
+		if err != nil {
+			return err
+		}
+
+		config.Start = w
+	}
+
+	res, err := tuning.Search(config)
+
+	if err != nil {
+		return fmt.Errorf("unable to tune weights: %w", err)
+	}
+
+	if err := heuristicbot.SaveWeights(*out, res.Best.Weights); err != nil {
+		return err
+	}
+
+	slog.Info("wrote weights", "score", res.Best.AverageScore, "trials", len(res.Trials), "path", *out)
+
+	return nil
+}
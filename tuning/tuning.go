@@ -0,0 +1,249 @@
+package tuning
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/timtatt/fivecrowns/bots"
+	"github.com/timtatt/fivecrowns/bots/heuristicbot"
+	"github.com/timtatt/fivecrowns/game"
+	"github.com/timtatt/fivecrowns/tournament"
+)
+
+// Tuning searches for heuristicbot weights by playing each candidate against a fixed set of opponents
+// every candidate faces the same deals, so the difference between two candidates is not down to luck
+
+type Method string
+
+const (
+	// tries evenly spaced values of every weight
+	MethodGrid Method = "grid"
+	// tries weights picked at random from their range
+	MethodRandom Method = "random"
+	// mutates the best weights found so far, narrowing the mutations when they stop helping
+	MethodEvolve Method = "evolve"
+)
+
+// Param is a weight which can be tuned and the range it is searched over
+type Param struct {
+	Name     string
+	Min, Max float64
+	get      func(w *heuristicbot.Weights) *float64
+}
+
+var Params = []Param{
+	{Name: "setBias", Min: -1, Max: 1, get: func(w *heuristicbot.Weights) *float64 { return &w.SetBias }},
+	{Name: "partial", Min: 0, Max: 1, get: func(w *heuristicbot.Weights) *float64 { return &w.Partial }},
+	{Name: "wildHoarding", Min: 0, Max: 10, get: func(w *heuristicbot.Weights) *float64 { return &w.WildHoarding }},
+	{Name: "highCardShedding", Min: 0, Max: 1, get: func(w *heuristicbot.Weights) *float64 { return &w.HighCardShedding }},
+	{Name: "lastTurnAggression", Min: 0, Max: 1, get: func(w *heuristicbot.Weights) *float64 { return &w.LastTurnAggression }},
+	{Name: "damageControl", Min: 0, Max: 1, get: func(w *heuristicbot.Weights) *float64 { return &w.DamageControl }},
+	{Name: "drawThreshold", Min: 0, Max: 10, get: func(w *heuristicbot.Weights) *float64 { return &w.DrawThreshold }},
+}
+
+type Config struct {
+	Method Method
+	// number of candidates to try
+	Budget int
+	// bots the candidates play against. every match seats the candidate with all of the opponents
+	Opponents []tournament.Entrant
+	// number of matches played by each candidate
+	Matches int
+	Seed    uint64
+	// weights the evolutionary search starts from. defaults to heuristicbot.DefaultWeights
+	Start heuristicbot.Weights
+	// the rules every match is played with, nil for the house rules
+	Rules *game.RuleSet
+}
+
+type Trial struct {
+	Weights heuristicbot.Weights
+	// the candidate's average penalty per round, lower is better
+	AverageScore float64
+}
+
+type Result struct {
+	Best   Trial
+	Trials []Trial
+}
+
+const candidateName = "candidate"
+
+func Search(config Config) (Result, error) {
+
+	if config.Budget < 1 {
+		return Result{}, fmt.Errorf("invalid budget: %d", config.Budget)
+	} else if len(config.Opponents) < 1 {
+		return Result{}, errors.New("unable to tune without opponents")
+	}
+
+	if config.Start == (heuristicbot.Weights{}) {
+		config.Start = heuristicbot.DefaultWeights
+	}
+
+	r := rand.New(rand.NewPCG(config.Seed, 0))
+	res := Result{Best: Trial{AverageScore: math.Inf(1)}}
+
+	try := func(w heuristicbot.Weights) (Trial, error) {
+		score, err := Evaluate(w, config)
+
+		if err != nil {
+			return Trial{}, err
+		}
+
+		trial := Trial{Weights: w, AverageScore: score}
+		res.Trials = append(res.Trials, trial)
+
+		if trial.AverageScore < res.Best.AverageScore {
+			res.Best = trial
+		}
+
+		slog.Info("tried weights", "trial", len(res.Trials), "score", score, "best", res.Best.AverageScore)
+
+		return trial, nil
+	}
+
+	switch config.Method {
+	case MethodGrid:
+		weights, err := grid(config.Budget)
+
+		if err != nil {
+			return res, err
+		}
+
+		for _, w := range weights {
+			if _, err := try(w); err != nil {
+				return res, err
+			}
+		}
+	case MethodRandom:
+		for range config.Budget {
+			if _, err := try(random(r)); err != nil {
+				return res, err
+			}
+		}
+	case MethodEvolve:
+		if err := evolve(config, r, try); err != nil {
+			return res, err
+		}
+	default:
+		return Result{}, fmt.Errorf("unknown method: %s", config.Method)
+	}
+
+	return res, nil
+}
+
+// plays the weights against the opponents and returns their average penalty per round
+func Evaluate(w heuristicbot.Weights, config Config) (float64, error) {
+	entrants := append([]tournament.Entrant{{
+		Name:   candidateName,
+		NewBot: func() bots.Bot { return heuristicbot.NewHeuristicBot(w) },
+	}}, config.Opponents...)
+
+	res, err := tournament.Run(entrants, tournament.Config{
+		Format:    tournament.FormatRoundRobin,
+		TableSize: len(entrants),
+		Rounds:    max(config.Matches, 1),
+		Seed:      config.Seed,
+		Rules:     config.Rules,
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("unable to evaluate weights: %w", err)
+	}
+
+	idx := slices.IndexFunc(res.Standings, func(s tournament.Standing) bool { return s.Name == candidateName })
+
+	return res.Standings[idx].AverageScore, nil
+}
+
+// returns at most budget weights, trying the same number of evenly spaced values for every param
+// errors when the budget is too small to try two values of every param
+func grid(budget int) ([]heuristicbot.Weights, error) {
+	steps := 1
+	for math.Pow(float64(steps+1), float64(len(Params))) <= float64(budget) {
+		steps += 1
+	}
+
+	if steps == 1 {
+		return nil, fmt.Errorf("grid search needs a budget of at least %d to try two values of every weight, got %d", 1<<len(Params), budget)
+	}
+
+	out := []heuristicbot.Weights{heuristicbot.DefaultWeights}
+
+	for _, p := range Params {
+		next := make([]heuristicbot.Weights, 0, len(out)*steps)
+
+		for _, w := range out {
+			for step := range steps {
+				*p.get(&w) = p.Min + (p.Max-p.Min)*float64(step)/float64(steps-1)
+				next = append(next, w)
+			}
+		}
+
+		out = next
+	}
+
+	return out, nil
+}
+
+func random(r *rand.Rand) heuristicbot.Weights {
+	w := heuristicbot.DefaultWeights
+
+	for _, p := range Params {
+		*p.get(&w) = p.Min + (p.Max-p.Min)*r.Float64()
+	}
+
+	return w
+}
+
+// number of mutations tried from the best weights in each generation
+const offspring = 4
+
+// a (1+λ) evolution strategy. the mutation size halves after a generation without improvement
+func evolve(config Config, r *rand.Rand, try func(w heuristicbot.Weights) (Trial, error)) error {
+	best, err := try(config.Start)
+
+	if err != nil {
+		return err
+	}
+
+	sigma := 0.25
+
+	for tried := 1; tried < config.Budget; {
+		improved := false
+		parent := best.Weights
+
+		for range min(offspring, config.Budget-tried) {
+			child := parent
+
+			for _, p := range Params {
+				v := p.get(&child)
+				*v = min(max(*v+r.NormFloat64()*sigma*(p.Max-p.Min), p.Min), p.Max)
+			}
+
+			trial, err := try(child)
+
+			if err != nil {
+				return err
+			}
+
+			tried += 1
+
+			if trial.AverageScore < best.AverageScore {
+				best = trial
+				improved = true
+			}
+		}
+
+		if !improved {
+			sigma /= 2
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package tuning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timtatt/fivecrowns/bots/grugbot"
+	"github.com/timtatt/fivecrowns/bots/heuristicbot"
+	"github.com/timtatt/fivecrowns/tournament"
+)
+
+var opponents = []tournament.Entrant{
+	{Name: "grugbot", NewBot: grugbot.NewGrugBot},
+}
+
+func TestGrid(t *testing.T) {
+
+	cases := []struct {
+		Budget   int
+		Expected int
+	}{
+		{Budget: 128, Expected: 128},
+		{Budget: 2186, Expected: 128},
+		{Budget: 2187, Expected: 2187},
+	}
+
+	for _, c := range cases {
+		weights, err := grid(c.Budget)
+		require.NoError(t, err, "budget %d", c.Budget)
+		assert.Len(t, weights, c.Expected, "budget %d", c.Budget)
+	}
+
+	// too small to try two values of every param
+	for _, budget := range []int{1, 50, 127} {
+		_, err := grid(budget)
+		assert.Error(t, err, "budget %d", budget)
+	}
+
+	// every param spans its whole range
+	weights, err := grid(128)
+	require.NoError(t, err)
+	for _, p := range Params {
+		assert.Equal(t, p.Min, *p.get(&weights[0]), p.Name)
+		assert.Equal(t, p.Max, *p.get(&weights[len(weights)-1]), p.Name)
+	}
+}
+
+func TestSearch(t *testing.T) {
+
+	cases := []struct {
+		Method Method
+		Budget int
+	}{
+		{Method: MethodRandom, Budget: 2},
+		{Method: MethodEvolve, Budget: 3},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.Method), func(t *testing.T) {
+			res, err := Search(Config{
+				Method:    c.Method,
+				Budget:    c.Budget,
+				Opponents: opponents,
+				Matches:   1,
+				Seed:      1,
+			})
+
+			require.NoError(t, err)
+			require.Len(t, res.Trials, c.Budget)
+
+			for _, trial := range res.Trials {
+				assert.LessOrEqual(t, res.Best.AverageScore, trial.AverageScore)
+			}
+		})
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+
+	cases := []struct {
+		Name   string
+		Config Config
+	}{
+		{Name: "unknown method", Config: Config{Method: "anneal", Budget: 1, Opponents: opponents}},
+		{Name: "no budget", Config: Config{Method: MethodRandom, Opponents: opponents}},
+		{Name: "no opponents", Config: Config{Method: MethodRandom, Budget: 1}},
+		{Name: "grid budget too small", Config: Config{Method: MethodGrid, Budget: 50, Opponents: opponents}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			_, err := Search(c.Config)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	config := Config{Opponents: opponents, Matches: 1, Seed: 3}
+
+	score, err := Evaluate(heuristicbot.DefaultWeights, config)
+	require.NoError(t, err)
+
+	// the same seed deals the same cards
+	again, err := Evaluate(heuristicbot.DefaultWeights, config)
+	require.NoError(t, err)
+	assert.Equal(t, score, again)
+}